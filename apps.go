@@ -0,0 +1,410 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// appJWTLifetime is how long a signed app JWT is valid. GitHub
+	// rejects tokens that expire more than ten minutes in the future.
+	appJWTLifetime = 9 * time.Minute
+
+	// appJWTClockSkew backdates the issued-at claim to tolerate clock drift.
+	appJWTClockSkew = time.Minute
+
+	// tokenRefreshMargin is how long before expiry a cached token is renewed.
+	tokenRefreshMargin = 5 * time.Minute
+
+	// installationTokenLifetime is how long installation tokens are valid,
+	// assumed when a token is returned without an expiry.
+	installationTokenLifetime = time.Hour
+)
+
+// AppsService provides access to GitHub App-related API methods.
+// Most methods require the client to be authenticated as an app with
+// WithAppAuth.
+type AppsService struct {
+	client *Client
+}
+
+// Installation represents an installation of a GitHub App.
+// GitHub API docs: https://docs.github.com/en/rest/apps/apps
+type Installation struct {
+	ID                  int64             `json:"id"`
+	NodeID              string            `json:"node_id"`
+	AppID               int64             `json:"app_id"`
+	AppSlug             string            `json:"app_slug"`
+	TargetID            int64             `json:"target_id"`
+	TargetType          string            `json:"target_type"`
+	Account             *User             `json:"account"`
+	RepositorySelection string            `json:"repository_selection"`
+	AccessTokensURL     string            `json:"access_tokens_url"`
+	RepositoriesURL     string            `json:"repositories_url"`
+	HTMLURL             string            `json:"html_url"`
+	Permissions         map[string]string `json:"permissions"`
+	Events              []string          `json:"events"`
+	CreatedAt           *Timestamp        `json:"created_at"`
+	UpdatedAt           *Timestamp        `json:"updated_at"`
+	SuspendedAt         *Timestamp        `json:"suspended_at"`
+}
+
+// InstallationToken represents an installation access token.
+// GitHub API docs: https://docs.github.com/en/rest/apps/apps#create-an-installation-access-token-for-an-app
+type InstallationToken struct {
+	Token               string            `json:"token"`
+	ExpiresAt           *Timestamp        `json:"expires_at"`
+	Permissions         map[string]string `json:"permissions"`
+	RepositorySelection string            `json:"repository_selection"`
+	Repositories        []*Repository     `json:"repositories"`
+}
+
+// InstallationTokenRequest represents the request body for creating an
+// installation access token scoped to a subset of repositories or permissions.
+// GitHub API docs: https://docs.github.com/en/rest/apps/apps#create-an-installation-access-token-for-an-app
+type InstallationTokenRequest struct {
+	Repositories  []string          `json:"repositories,omitempty"`
+	RepositoryIDs []int64           `json:"repository_ids,omitempty"`
+	Permissions   map[string]string `json:"permissions,omitempty"`
+}
+
+// ListInstallations lists the installations of the authenticated app.
+// The results are returned in pages according to the pagination options.
+func (s *AppsService) ListInstallations(ctx context.Context, opts *ListOptions, reqOpts ...RequestOption) ([]*Installation, *Response, error) {
+	path := "app/installations"
+
+	if opts != nil {
+		v := url.Values{}
+		opts.Apply(v)
+
+		if len(v) != 0 {
+			path += "?" + v.Encode()
+		}
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	installations := new([]*Installation)
+
+	resp, err := s.client.Do(ctx, req, installations, reqOpts...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return *installations, resp, nil
+}
+
+// ListInstallationsAll returns an iterator over all installations of the
+// authenticated app, transparently following pagination links.
+func (s *AppsService) ListInstallationsAll(ctx context.Context, opts *ListOptions, reqOpts ...RequestOption) iter.Seq2[*Installation, error] {
+	return paginate(ctx, opts, func(ctx context.Context, lo *ListOptions) ([]*Installation, *Response, error) {
+		return s.ListInstallations(ctx, lo, reqOpts...)
+	})
+}
+
+// GetInstallation fetches a single installation of the authenticated app.
+func (s *AppsService) GetInstallation(ctx context.Context, installationID int64, reqOpts ...RequestOption) (*Installation, *Response, error) {
+	path := fmt.Sprintf("app/installations/%d", installationID)
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	installation := new(Installation)
+
+	resp, err := s.client.Do(ctx, req, installation, reqOpts...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return installation, resp, nil
+}
+
+// CreateInstallationToken creates an access token for an installation.
+// The token expires after one hour. The body is optional and can be used
+// to restrict the token to specific repositories or permissions.
+func (s *AppsService) CreateInstallationToken(
+	ctx context.Context,
+	installationID int64,
+	body *InstallationTokenRequest,
+	reqOpts ...RequestOption,
+) (*InstallationToken, *Response, error) {
+	path := fmt.Sprintf("app/installations/%d/access_tokens", installationID)
+
+	var payload any
+	if body != nil {
+		payload = body
+	}
+
+	req, err := s.client.NewRequest(http.MethodPost, path, payload, reqOpts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token := new(InstallationToken)
+
+	resp, err := s.client.Do(ctx, req, token, reqOpts...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return token, resp, nil
+}
+
+// NewInstallationClient creates a client authenticated as the given
+// installation of the app. The new client shares the HTTP client, base URL,
+// user agent and retry settings of the app client and refreshes its
+// installation token automatically. Additional options are applied last.
+func (s *AppsService) NewInstallationClient(installationID int64, opts ...option) (*Client, error) {
+	if s.client.app == nil {
+		return nil, errors.New("client is not authenticated as an app")
+	}
+
+	base := append(s.client.inheritedOptions(), func(c *Client) error {
+		c.tokenSource = &installationTokenSource{
+			apps:           func() (*AppsService, error) { return s, nil },
+			installationID: installationID,
+		}
+
+		return nil
+	})
+
+	return NewClient(append(base, opts...)...)
+}
+
+// inheritedOptions returns the options needed to create a client that talks
+// to the same server in the same way as c.
+func (c *Client) inheritedOptions() []option {
+	return []option{
+		WithHTTPClient(c.client),
+		withEndpoints(c.baseURL, c.uploadURL, c.graphQLURL),
+		WithUserAgent(c.userAgent),
+		WithRateLimitRetry(c.rateLimitRetry),
+		WithRetryMax(c.retryMax),
+		WithRetryWaitMin(c.retryWaitMin),
+		WithRetryWaitMax(c.retryWaitMax),
+		WithRetryPolicy(c.retryPolicy),
+		WithMiddleware(c.middleware...),
+		WithRequestHook(c.requestHook),
+		WithResponseHook(c.responseHook),
+		WithLogger(c.logger),
+		WithLogOptions(c.logOptions),
+		WithInstrumentation(c.instrumentation),
+	}
+}
+
+// appTokenSource signs JWTs that authenticate requests as a GitHub App.
+type appTokenSource struct {
+	appID string
+	key   *rsa.PrivateKey
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func newAppTokenSource(appID int64, privateKey []byte) (*appTokenSource, error) {
+	key, err := parseRSAPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &appTokenSource{appID: strconv.FormatInt(appID, 10), key: key}, nil
+}
+
+// Invalidate discards the cached JWT.
+func (a *appTokenSource) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.token = ""
+}
+
+// Token returns a cached JWT, signing a new one when the cached token
+// is about to expire.
+func (a *appTokenSource) Token(_ context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if a.token != "" && now.Add(appJWTClockSkew).Before(a.expiresAt) {
+		return a.token, nil
+	}
+
+	expiresAt := now.Add(appJWTLifetime)
+
+	token, err := signAppJWT(a.key, a.appID, now.Add(-appJWTClockSkew), expiresAt)
+	if err != nil {
+		return "", err
+	}
+
+	a.token = token
+	a.expiresAt = expiresAt
+
+	return token, nil
+}
+
+func signAppJWT(key *rsa.PrivateKey, issuer string, issuedAt time.Time, expiresAt time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]any{
+		"iat": issuedAt.Unix(),
+		"exp": expiresAt.Unix(),
+		"iss": issuer,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app JWT: %w", err)
+	}
+
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode private key PEM")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+
+	return key, nil
+}
+
+// installationTokenSource exchanges app JWTs for installation access
+// tokens and caches them until shortly before they expire. Concurrent
+// callers share a single request for a new token.
+type installationTokenSource struct {
+	apps           func() (*AppsService, error)
+	installationID int64
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+	refresh   *tokenRefresh
+}
+
+// tokenRefresh is a request for a new installation token in flight.
+type tokenRefresh struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+// Invalidate discards the cached installation token.
+func (i *installationTokenSource) Invalidate() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.token = ""
+}
+
+// Token returns the cached installation token, creating a new one when
+// the cached token expires within tokenRefreshMargin. The token is created
+// without holding the lock, and callers that arrive meanwhile wait for it.
+func (i *installationTokenSource) Token(ctx context.Context) (string, error) {
+	i.mu.Lock()
+
+	if i.token != "" && time.Now().Add(tokenRefreshMargin).Before(i.expiresAt) {
+		token := i.token
+		i.mu.Unlock()
+
+		return token, nil
+	}
+
+	r := i.refresh
+	if r == nil {
+		r = &tokenRefresh{done: make(chan struct{})}
+		i.refresh = r
+		i.mu.Unlock()
+
+		i.fetch(ctx, r)
+	} else {
+		i.mu.Unlock()
+	}
+
+	select {
+	case <-r.done:
+		return r.token, r.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// fetch creates a new installation token, caches it and hands it to the
+// callers waiting for r.
+func (i *installationTokenSource) fetch(ctx context.Context, r *tokenRefresh) {
+	defer close(r.done)
+
+	var token *InstallationToken
+
+	apps, err := i.apps()
+	if err == nil {
+		token, _, err = apps.CreateInstallationToken(ctx, i.installationID, nil)
+		if err != nil {
+			err = fmt.Errorf("failed to create installation token: %w", err)
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.refresh = nil
+
+	if err != nil {
+		r.err = err
+		return
+	}
+
+	i.token = token.Token
+	i.expiresAt = time.Now().Add(installationTokenLifetime)
+
+	if token.ExpiresAt != nil && !token.ExpiresAt.IsZero() {
+		i.expiresAt = token.ExpiresAt.Time
+	}
+
+	r.token = i.token
+}
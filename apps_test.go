@@ -0,0 +1,283 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateTestKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pemKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	return key, pemKey
+}
+
+func verifyTestJWT(t *testing.T, key *rsa.PrivateKey, auth string) map[string]any {
+	t.Helper()
+
+	token, ok := strings.CutPrefix(auth, "Bearer ")
+	require.True(t, ok)
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig))
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+
+	claims := map[string]any{}
+	require.NoError(t, json.Unmarshal(raw, &claims))
+
+	return claims
+}
+
+func TestAppsService_ListInstallations(t *testing.T) {
+	key, pemKey := generateTestKey(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/installations", r.URL.Path)
+		assert.Equal(t, "GET", r.Method)
+
+		claims := verifyTestJWT(t, key, r.Header.Get("Authorization"))
+		assert.Equal(t, "42", claims["iss"])
+
+		iat := int64(claims["iat"].(float64))
+		exp := int64(claims["exp"].(float64))
+		assert.LessOrEqual(t, exp-iat, int64(10*time.Minute/time.Second))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": 1, "app_id": 42, "account": {"login": "octocat"}, "target_type": "User"}]`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithAppAuth(42, pemKey))
+	require.NoError(t, err)
+
+	installations, _, err := client.Apps.ListInstallations(context.Background(), nil)
+	require.NoError(t, err)
+
+	require.Len(t, installations, 1)
+	assert.Equal(t, int64(1), installations[0].ID)
+	assert.Equal(t, "octocat", installations[0].Account.Login)
+}
+
+func TestAppsService_CreateInstallationToken(t *testing.T) {
+	_, pemKey := generateTestKey(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/app/installations/7/access_tokens", r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+
+		body := new(InstallationTokenRequest)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(body))
+		assert.Equal(t, []string{"Hello-World"}, body.Repositories)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token": "ghs_abc", "expires_at": "2030-01-01T00:00:00Z", "permissions": {"issues": "write"}}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithAppAuth(42, pemKey))
+	require.NoError(t, err)
+
+	token, _, err := client.Apps.CreateInstallationToken(context.Background(), 7, &InstallationTokenRequest{
+		Repositories: []string{"Hello-World"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ghs_abc", token.Token)
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), token.ExpiresAt.Time)
+	assert.Equal(t, map[string]string{"issues": "write"}, token.Permissions)
+}
+
+func TestInstallationAuth_CachesAndRefreshes(t *testing.T) {
+	tests := []struct {
+		name           string
+		expiresAt      time.Time
+		expectedTokens int32
+	}{
+		{
+			name:           "token cached until close to expiry",
+			expiresAt:      time.Now().Add(time.Hour),
+			expectedTokens: 1,
+		},
+		{
+			name:           "token refreshed before expiry",
+			expiresAt:      time.Now().Add(time.Minute),
+			expectedTokens: 3,
+		},
+		{
+			name:           "token without expiry cached",
+			expectedTokens: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, pemKey := generateTestKey(t)
+
+			var issued atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/app/installations/7/access_tokens" {
+					verifyTestJWT(t, key, r.Header.Get("Authorization"))
+
+					n := issued.Add(1)
+					if tt.expiresAt.IsZero() {
+						_, _ = fmt.Fprintf(w, `{"token": "ghs_%d"}`, n)
+						return
+					}
+
+					_, _ = fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`, n, tt.expiresAt.UTC().Format(time.RFC3339))
+
+					return
+				}
+
+				assert.Equal(t, fmt.Sprintf("Bearer ghs_%d", issued.Load()), r.Header.Get("Authorization"))
+				_, _ = w.Write([]byte(`{"login": "octocat"}`))
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(WithBaseURL(ts.URL), WithInstallationAuth(42, 7, pemKey))
+			require.NoError(t, err)
+
+			for range 3 {
+				_, _, err := client.Users.Get(context.Background(), "octocat")
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectedTokens, issued.Load())
+		})
+	}
+}
+
+func TestInstallationAuth_ConcurrentRefresh(t *testing.T) {
+	_, pemKey := generateTestKey(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	var issued atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app/installations/7/access_tokens" {
+			if issued.Add(1) == 1 {
+				close(started)
+			}
+
+			<-release
+			_, _ = w.Write([]byte(`{"token": "ghs_1", "expires_at": "2030-01-01T00:00:00Z"}`))
+
+			return
+		}
+
+		assert.Equal(t, "Bearer ghs_1", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithInstallationAuth(42, 7, pemKey))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, _, err := client.Users.Get(context.Background(), "octocat")
+			assert.NoError(t, err)
+		}()
+	}
+
+	<-started
+
+	// a caller waiting for the token in flight gives up with its context
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, _, err := client.Users.Get(ctx, "octocat")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Error("caller blocked behind the token request")
+	}
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), issued.Load())
+}
+
+func TestAppsService_NewInstallationClient(t *testing.T) {
+	_, pemKey := generateTestKey(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app/installations/7/access_tokens" {
+			_, _ = w.Write([]byte(`{"token": "ghs_installation", "expires_at": "2030-01-01T00:00:00Z"}`))
+			return
+		}
+
+		assert.Equal(t, "Bearer ghs_installation", r.Header.Get("Authorization"))
+		assert.Equal(t, "my-app", r.Header.Get("User-Agent"))
+		_, _ = w.Write([]byte(`{"id": 1, "name": "Hello-World"}`))
+	}))
+	defer ts.Close()
+
+	app, err := NewClient(WithBaseURL(ts.URL), WithUserAgent("my-app"), WithAppAuth(42, pemKey))
+	require.NoError(t, err)
+
+	client, err := app.Apps.NewInstallationClient(7)
+	require.NoError(t, err)
+
+	repo, _, err := client.Repositories.Get(context.Background(), "octocat", "Hello-World")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello-World", repo.Name)
+
+	plain, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	_, err = plain.Apps.NewInstallationClient(7)
+	require.Error(t, err)
+}
+
+func TestWithAppAuth_InvalidKey(t *testing.T) {
+	_, err := NewClient(WithAppAuth(42, []byte("not a key")))
+	require.Error(t, err)
+}
@@ -0,0 +1,712 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	// graphQLNodeLimit is the maximum number of nodes a single GraphQL
+	// call may request
+	graphQLNodeLimit = 500_000
+
+	defaultBatchSize        = 100
+	defaultBatchConcurrency = 4
+
+	// page sizes of the connections selected for every batched object
+	batchLabels = 20
+	batchUsers  = 10
+	batchTopics = 20
+)
+
+// IssueKey identifies an issue or pull request by its repository and number.
+type IssueKey struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// RepositoryKey identifies a repository by its owner and name.
+type RepositoryKey struct {
+	Owner string
+	Name  string
+}
+
+// BatchLoader looks up many issues, pull requests, users or repositories
+// with few GraphQL calls. The keys are grouped into queries with one
+// aliased field per key, each query staying below the node limit of the
+// GraphQL API, and the queries are sent concurrently. The results are
+// mapped into the same structs the REST services return.
+//
+// Objects that do not exist or are not visible are missing from the
+// results. Other errors are returned along with the results of the
+// queries that succeeded.
+type BatchLoader struct {
+	client      *Client
+	batchSize   int
+	concurrency int
+	maxNodes    int
+}
+
+// NewBatchLoader creates a batch loader that sends queries through client.
+// Each query looks up at most batchSize keys and at most concurrency
+// queries are in flight at once. Values below 1 select the defaults of
+// 100 keys and 4 queries.
+func NewBatchLoader(client *Client, batchSize, concurrency int) *BatchLoader {
+	if batchSize < 1 {
+		batchSize = defaultBatchSize
+	}
+
+	if concurrency < 1 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	return &BatchLoader{
+		client:      client,
+		batchSize:   batchSize,
+		concurrency: concurrency,
+		maxNodes:    graphQLNodeLimit,
+	}
+}
+
+// Issues looks up the issues identified by keys.
+func (l *BatchLoader) Issues(ctx context.Context, keys []IssueKey, reqOpts ...RequestOption) (map[IssueKey]*Issue, error) {
+	return runBatch(ctx, l, keys, batchQuery[IssueKey, *Issue]{
+		nodes:     1 + batchLabels + batchUsers,
+		fragments: []string{issueFragment, labelFragment, actorFragment},
+		field: func(alias string, key IssueKey, vars map[string]any) (string, string) {
+			vars[alias+"o"], vars[alias+"r"], vars[alias+"n"] = key.Owner, key.Repo, key.Number
+
+			decl := fmt.Sprintf("$%[1]so: String!, $%[1]sr: String!, $%[1]sn: Int!", alias)
+			sel := fmt.Sprintf("%[1]s: repository(owner: $%[1]so, name: $%[1]sr) { issue(number: $%[1]sn) { ...issueFields } }", alias)
+
+			return decl, sel
+		},
+		decode: func(data json.RawMessage, key IssueKey) (*Issue, bool, error) {
+			var v struct {
+				Issue *gqlIssue `json:"issue"`
+			}
+			if err := json.Unmarshal(data, &v); err != nil || v.Issue == nil {
+				return nil, false, err
+			}
+
+			return v.Issue.toIssue(l.client, key), true, nil
+		},
+	}, reqOpts)
+}
+
+// PullRequests looks up the pull requests identified by keys.
+func (l *BatchLoader) PullRequests(ctx context.Context, keys []IssueKey, reqOpts ...RequestOption) (map[IssueKey]*PullRequest, error) {
+	return runBatch(ctx, l, keys, batchQuery[IssueKey, *PullRequest]{
+		nodes:     1 + batchLabels + 2*batchUsers + 1 + batchTopics,
+		fragments: []string{pullRequestFragment, repositoryFragment, labelFragment, actorFragment},
+		field: func(alias string, key IssueKey, vars map[string]any) (string, string) {
+			vars[alias+"o"], vars[alias+"r"], vars[alias+"n"] = key.Owner, key.Repo, key.Number
+
+			decl := fmt.Sprintf("$%[1]so: String!, $%[1]sr: String!, $%[1]sn: Int!", alias)
+			sel := fmt.Sprintf("%[1]s: repository(owner: $%[1]so, name: $%[1]sr) { pullRequest(number: $%[1]sn) { ...pullRequestFields } }", alias)
+
+			return decl, sel
+		},
+		decode: func(data json.RawMessage, key IssueKey) (*PullRequest, bool, error) {
+			var v struct {
+				PullRequest *gqlPullRequest `json:"pullRequest"`
+			}
+			if err := json.Unmarshal(data, &v); err != nil || v.PullRequest == nil {
+				return nil, false, err
+			}
+
+			return v.PullRequest.toPullRequest(l.client, key), true, nil
+		},
+	}, reqOpts)
+}
+
+// Users looks up the users with the given logins. Organizations are not
+// users in the GraphQL API and are missing from the results.
+func (l *BatchLoader) Users(ctx context.Context, logins []string, reqOpts ...RequestOption) (map[string]*User, error) {
+	return runBatch(ctx, l, logins, batchQuery[string, *User]{
+		nodes:     1,
+		fragments: []string{userFragment},
+		field: func(alias string, login string, vars map[string]any) (string, string) {
+			vars[alias+"l"] = login
+
+			return fmt.Sprintf("$%sl: String!", alias), fmt.Sprintf("%[1]s: user(login: $%[1]sl) { ...userFields }", alias)
+		},
+		decode: func(data json.RawMessage, _ string) (*User, bool, error) {
+			var v *gqlUser
+			if err := json.Unmarshal(data, &v); err != nil || v == nil {
+				return nil, false, err
+			}
+
+			return v.toUser(l.client), true, nil
+		},
+	}, reqOpts)
+}
+
+// Repositories looks up the repositories identified by keys.
+func (l *BatchLoader) Repositories(ctx context.Context, keys []RepositoryKey, reqOpts ...RequestOption) (map[RepositoryKey]*Repository, error) {
+	return runBatch(ctx, l, keys, batchQuery[RepositoryKey, *Repository]{
+		nodes:     1 + batchTopics,
+		fragments: []string{repositoryFragment},
+		field: func(alias string, key RepositoryKey, vars map[string]any) (string, string) {
+			vars[alias+"o"], vars[alias+"r"] = key.Owner, key.Name
+
+			decl := fmt.Sprintf("$%[1]so: String!, $%[1]sr: String!", alias)
+			sel := fmt.Sprintf("%[1]s: repository(owner: $%[1]so, name: $%[1]sr) { ...repositoryFields }", alias)
+
+			return decl, sel
+		},
+		decode: func(data json.RawMessage, _ RepositoryKey) (*Repository, bool, error) {
+			var v *gqlRepository
+			if err := json.Unmarshal(data, &v); err != nil || v == nil {
+				return nil, false, err
+			}
+
+			return v.toRepository(l.client), true, nil
+		},
+	}, reqOpts)
+}
+
+// batchQuery describes how to look up objects of type T by keys of type K.
+type batchQuery[K comparable, T any] struct {
+	// nodes is the number of nodes the lookup of a single key requests
+	nodes int
+
+	// fragments are the fragment definitions the fields refer to
+	fragments []string
+
+	// field returns the variable declarations and the aliased selection
+	// for key, and sets the variables it declares
+	field func(alias string, key K, vars map[string]any) (decl string, sel string)
+
+	// decode maps the aliased field of the response for key to its
+	// object and reports whether the object exists
+	decode func(data json.RawMessage, key K) (T, bool, error)
+}
+
+// runBatch looks up keys in chunks that respect the batch size and the
+// node limit, running up to l.concurrency chunks at once.
+func runBatch[K comparable, T any](ctx context.Context, l *BatchLoader, keys []K, q batchQuery[K, T], reqOpts []RequestOption) (map[K]T, error) {
+	unique := make([]K, 0, len(keys))
+	seen := make(map[K]bool, len(keys))
+
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+
+	size := max(min(l.batchSize, l.maxNodes/q.nodes), 1)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		errs    []error
+		results = make(map[K]T, len(unique))
+		sem     = make(chan struct{}, l.concurrency)
+	)
+
+	for start := 0; start < len(unique); start += size {
+		chunk := unique[start:min(start+size, len(unique))]
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return results, errors.Join(append(errs, ctx.Err())...)
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			found, err := runChunk(ctx, l.client, chunk, q, reqOpts)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			for key, v := range found {
+				results[key] = v
+			}
+
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
+
+// runChunk looks up keys with a single GraphQL query.
+func runChunk[K comparable, T any](ctx context.Context, c *Client, keys []K, q batchQuery[K, T], reqOpts []RequestOption) (map[K]T, error) {
+	vars := make(map[string]any, len(keys))
+	decls := make([]string, len(keys))
+	sels := make([]string, len(keys))
+
+	for i, key := range keys {
+		decls[i], sels[i] = q.field(fmt.Sprintf("k%d", i), key, vars)
+	}
+
+	query := fmt.Sprintf("query(%s) {\n%s\n}\n%s",
+		strings.Join(decls, ", "), strings.Join(sels, "\n"), strings.Join(q.fragments, "\n"))
+
+	var data map[string]json.RawMessage
+
+	_, err := c.GraphQL(ctx, query, vars, &data, reqOpts...)
+
+	var gqlErr *GraphQLError
+	if errors.As(err, &gqlErr) {
+		err = withoutNotFound(gqlErr)
+	}
+
+	results := make(map[K]T, len(keys))
+
+	for i, key := range keys {
+		raw, ok := data[fmt.Sprintf("k%d", i)]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+
+		v, found, decodeErr := q.decode(raw, key)
+		if decodeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to decode %v: %w", key, decodeErr))
+			continue
+		}
+
+		if found {
+			results[key] = v
+		}
+	}
+
+	return results, err
+}
+
+// withoutNotFound returns the errors of e other than NOT_FOUND, which
+// only mark missing objects, or nil if there are none.
+func withoutNotFound(e *GraphQLError) error {
+	var rest []GraphQLErrorDetail
+
+	for _, d := range e.Errors {
+		if d.Type != "NOT_FOUND" {
+			rest = append(rest, d)
+		}
+	}
+
+	if len(rest) == 0 {
+		return nil
+	}
+
+	return &GraphQLError{Errors: rest}
+}
+
+const actorFragment = `fragment actorFields on Actor {
+  __typename login avatarUrl
+  ... on User { id databaseId }
+  ... on Bot { id databaseId }
+  ... on Organization { id databaseId }
+}`
+
+var issueFragment = fmt.Sprintf(`fragment issueFields on Issue {
+  id databaseId number state title body bodyHTML bodyText locked createdAt updatedAt closedAt
+  repository { nameWithOwner }
+  author { ...actorFields }
+  labels(first: %d) { nodes { ...labelFields } }
+  assignees(first: %d) { nodes { ...actorFields } }
+  comments { totalCount }
+}`, batchLabels, batchUsers)
+
+var pullRequestFragment = fmt.Sprintf(`fragment pullRequestFields on PullRequest {
+  id databaseId number state title body bodyHTML bodyText locked activeLockReason url headRefOid createdAt updatedAt closedAt
+  author { ...actorFields }
+  labels(first: %d) { nodes { ...labelFields } }
+  assignees(first: %d) { nodes { ...actorFields } }
+  reviewRequests(first: %d) { nodes { requestedReviewer { ...actorFields } } }
+  repository { ...repositoryFields }
+}`, batchLabels, batchUsers, batchUsers)
+
+const labelFragment = `fragment labelFields on Label { id name description color isDefault }`
+
+const userFragment = `fragment userFields on User {
+  id databaseId login avatarUrl name company websiteUrl location email isHireable bio createdAt updatedAt
+  repositories(privacy: PUBLIC) { totalCount }
+  followers { totalCount }
+  following { totalCount }
+}`
+
+var repositoryFragment = fmt.Sprintf(`fragment repositoryFields on Repository {
+  id databaseId name nameWithOwner description url mirrorUrl isPrivate isFork isTemplate isArchived isDisabled
+  visibility forkCount stargazerCount diskUsage hasIssuesEnabled hasProjectsEnabled hasWikiEnabled
+  viewerPermission pushedAt createdAt updatedAt
+  owner { __typename login avatarUrl ... on User { id databaseId } ... on Organization { id databaseId } }
+  primaryLanguage { name }
+  defaultBranchRef { name }
+  issues(states: OPEN) { totalCount }
+  pullRequests(states: OPEN) { totalCount }
+  repositoryTopics(first: %d) { nodes { topic { name } } }
+}`, batchTopics)
+
+type gqlCount struct {
+	TotalCount int `json:"totalCount"`
+}
+
+type gqlActor struct {
+	Typename   string `json:"__typename"`
+	ID         string `json:"id"`
+	DatabaseID int64  `json:"databaseId"`
+	Login      string `json:"login"`
+	AvatarURL  string `json:"avatarUrl"`
+}
+
+type gqlLabel struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+	IsDefault   bool   `json:"isDefault"`
+}
+
+type gqlIssue struct {
+	ID         string     `json:"id"`
+	DatabaseID int64      `json:"databaseId"`
+	Number     int        `json:"number"`
+	State      string     `json:"state"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	BodyHTML   string     `json:"bodyHTML"`
+	BodyText   string     `json:"bodyText"`
+	Locked     bool       `json:"locked"`
+	CreatedAt  *Timestamp `json:"createdAt"`
+	UpdatedAt  *Timestamp `json:"updatedAt"`
+	ClosedAt   *Timestamp `json:"closedAt"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+	Author *gqlActor `json:"author"`
+	Labels struct {
+		Nodes []gqlLabel `json:"nodes"`
+	} `json:"labels"`
+	Assignees struct {
+		Nodes []*gqlActor `json:"nodes"`
+	} `json:"assignees"`
+	Comments gqlCount `json:"comments"`
+}
+
+type gqlPullRequest struct {
+	ID               string     `json:"id"`
+	DatabaseID       int        `json:"databaseId"`
+	Number           int        `json:"number"`
+	State            string     `json:"state"`
+	Title            string     `json:"title"`
+	Body             string     `json:"body"`
+	BodyHTML         string     `json:"bodyHTML"`
+	BodyText         string     `json:"bodyText"`
+	Locked           bool       `json:"locked"`
+	ActiveLockReason string     `json:"activeLockReason"`
+	URL              string     `json:"url"`
+	HeadRefOid       string     `json:"headRefOid"`
+	CreatedAt        *Timestamp `json:"createdAt"`
+	UpdatedAt        *Timestamp `json:"updatedAt"`
+	ClosedAt         *Timestamp `json:"closedAt"`
+	Author           *gqlActor  `json:"author"`
+	Labels           struct {
+		Nodes []gqlLabel `json:"nodes"`
+	} `json:"labels"`
+	Assignees struct {
+		Nodes []*gqlActor `json:"nodes"`
+	} `json:"assignees"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer *gqlActor `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+	Repository *gqlRepository `json:"repository"`
+}
+
+type gqlUser struct {
+	ID           string     `json:"id"`
+	DatabaseID   int64      `json:"databaseId"`
+	Login        string     `json:"login"`
+	AvatarURL    string     `json:"avatarUrl"`
+	Name         string     `json:"name"`
+	Company      string     `json:"company"`
+	WebsiteURL   string     `json:"websiteUrl"`
+	Location     string     `json:"location"`
+	Email        string     `json:"email"`
+	IsHireable   bool       `json:"isHireable"`
+	Bio          string     `json:"bio"`
+	CreatedAt    *Timestamp `json:"createdAt"`
+	UpdatedAt    *Timestamp `json:"updatedAt"`
+	Repositories gqlCount   `json:"repositories"`
+	Followers    gqlCount   `json:"followers"`
+	Following    gqlCount   `json:"following"`
+}
+
+type gqlRepository struct {
+	ID                 string     `json:"id"`
+	DatabaseID         int64      `json:"databaseId"`
+	Name               string     `json:"name"`
+	NameWithOwner      string     `json:"nameWithOwner"`
+	Description        string     `json:"description"`
+	URL                string     `json:"url"`
+	MirrorURL          string     `json:"mirrorUrl"`
+	IsPrivate          bool       `json:"isPrivate"`
+	IsFork             bool       `json:"isFork"`
+	IsTemplate         bool       `json:"isTemplate"`
+	IsArchived         bool       `json:"isArchived"`
+	IsDisabled         bool       `json:"isDisabled"`
+	Visibility         string     `json:"visibility"`
+	ForkCount          int        `json:"forkCount"`
+	StargazerCount     int        `json:"stargazerCount"`
+	DiskUsage          int        `json:"diskUsage"`
+	HasIssuesEnabled   bool       `json:"hasIssuesEnabled"`
+	HasProjectsEnabled bool       `json:"hasProjectsEnabled"`
+	HasWikiEnabled     bool       `json:"hasWikiEnabled"`
+	ViewerPermission   string     `json:"viewerPermission"`
+	PushedAt           *Timestamp `json:"pushedAt"`
+	CreatedAt          *Timestamp `json:"createdAt"`
+	UpdatedAt          *Timestamp `json:"updatedAt"`
+	Owner              *gqlActor  `json:"owner"`
+	PrimaryLanguage    *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	Issues           gqlCount `json:"issues"`
+	PullRequests     gqlCount `json:"pullRequests"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+}
+
+// apiURL returns the REST API URL of the resource at path.
+func (c *Client) apiURL(format string, args ...any) string {
+	u, err := c.baseURL.Parse(fmt.Sprintf(format, args...))
+	if err != nil {
+		return ""
+	}
+
+	return u.String()
+}
+
+func (a *gqlActor) toUser(c *Client) *User {
+	if a == nil || a.Login == "" {
+		return nil
+	}
+
+	return &User{
+		ID:        a.DatabaseID,
+		NodeID:    a.ID,
+		Login:     a.Login,
+		AvatarURL: a.AvatarURL,
+		URL:       c.apiURL("users/%s", a.Login),
+		Type:      a.Typename,
+	}
+}
+
+func toUsers(c *Client, actors []*gqlActor) []*User {
+	users := make([]*User, 0, len(actors))
+	for _, a := range actors {
+		if u := a.toUser(c); u != nil {
+			users = append(users, u)
+		}
+	}
+
+	return users
+}
+
+func toLabels(c *Client, owner, repo string, nodes []gqlLabel) []*Label {
+	labels := make([]*Label, len(nodes))
+	for i, l := range nodes {
+		labels[i] = &Label{
+			NodeID:      l.ID,
+			URL:         c.apiURL("repos/%s/%s/labels/%s", owner, repo, l.Name),
+			Name:        l.Name,
+			Description: l.Description,
+			Color:       l.Color,
+			Default:     l.IsDefault,
+		}
+	}
+
+	return labels
+}
+
+func (i *gqlIssue) toIssue(c *Client, key IssueKey) *Issue {
+	issue := &Issue{
+		ID:            i.DatabaseID,
+		NodeID:        i.ID,
+		URL:           c.apiURL("repos/%s/%s/issues/%d", key.Owner, key.Repo, i.Number),
+		RepositoryURL: c.apiURL("repos/%s/%s", key.Owner, key.Repo),
+		Number:        i.Number,
+		State:         strings.ToLower(i.State),
+		Title:         i.Title,
+		Body:          i.Body,
+		BodyHTML:      i.BodyHTML,
+		BodyText:      i.BodyText,
+		Labels:        toLabels(c, key.Owner, key.Repo, i.Labels.Nodes),
+		User:          i.Author.toUser(c),
+		Assignees:     toUsers(c, i.Assignees.Nodes),
+		Locked:        i.Locked,
+		Comments:      i.Comments.TotalCount,
+		ClosedAt:      i.ClosedAt,
+		CreatedAt:     i.CreatedAt,
+		UpdatedAt:     i.UpdatedAt,
+	}
+
+	if len(issue.Assignees) > 0 {
+		issue.Assignee = issue.Assignees[0]
+	}
+
+	return issue
+}
+
+// lockReasons maps the GraphQL lock reasons to their REST names.
+var lockReasons = map[string]string{
+	"OFF_TOPIC":  "off-topic",
+	"TOO_HEATED": "too heated",
+	"RESOLVED":   "resolved",
+	"SPAM":       "spam",
+}
+
+func (p *gqlPullRequest) toPullRequest(c *Client, key IssueKey) *PullRequest {
+	state := strings.ToLower(p.State)
+	if state == "merged" {
+		state = "closed"
+	}
+
+	reviewers := make([]*gqlActor, len(p.ReviewRequests.Nodes))
+	for i, n := range p.ReviewRequests.Nodes {
+		reviewers[i] = n.RequestedReviewer
+	}
+
+	pr := &PullRequest{
+		ID:                 p.DatabaseID,
+		NodeID:             p.ID,
+		Title:              p.Title,
+		Body:               p.Body,
+		BodyHTML:           p.BodyHTML,
+		BodyText:           p.BodyText,
+		URL:                c.apiURL("repos/%s/%s/pulls/%d", key.Owner, key.Repo, p.Number),
+		Number:             p.Number,
+		State:              state,
+		Locked:             p.Locked,
+		ActiveLockReason:   lockReasons[p.ActiveLockReason],
+		Labels:             toLabels(c, key.Owner, key.Repo, p.Labels.Nodes),
+		CreatedAt:          p.CreatedAt,
+		UpdatedAt:          p.UpdatedAt,
+		ClosedAt:           p.ClosedAt,
+		Assignees:          toUsers(c, p.Assignees.Nodes),
+		RequestedReviewers: toUsers(c, reviewers),
+		User:               p.Author.toUser(c),
+		HTMLURL:            p.URL,
+		DiffURL:            p.URL + ".diff",
+		PatchURL:           p.URL + ".patch",
+		IssueURL:           c.apiURL("repos/%s/%s/issues/%d", key.Owner, key.Repo, p.Number),
+		CommitsURL:         c.apiURL("repos/%s/%s/pulls/%d/commits", key.Owner, key.Repo, p.Number),
+		CommentsURL:        c.apiURL("repos/%s/%s/issues/%d/comments", key.Owner, key.Repo, p.Number),
+		StatusesURL:        c.apiURL("repos/%s/%s/statuses/%s", key.Owner, key.Repo, p.HeadRefOid),
+	}
+
+	if len(pr.Assignees) > 0 {
+		pr.Assignee = pr.Assignees[0]
+	}
+
+	if p.Repository != nil {
+		pr.Repository = p.Repository.toRepository(c)
+	}
+
+	return pr
+}
+
+func (u *gqlUser) toUser(c *Client) *User {
+	return &User{
+		ID:          u.DatabaseID,
+		Login:       u.Login,
+		NodeID:      u.ID,
+		AvatarURL:   u.AvatarURL,
+		URL:         c.apiURL("users/%s", u.Login),
+		Type:        "User",
+		Name:        u.Name,
+		Company:     u.Company,
+		Blog:        u.WebsiteURL,
+		Location:    u.Location,
+		Email:       u.Email,
+		Hireable:    u.IsHireable,
+		Bio:         u.Bio,
+		PublicRepos: u.Repositories.TotalCount,
+		Followers:   u.Followers.TotalCount,
+		Following:   u.Following.TotalCount,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+	}
+}
+
+func (r *gqlRepository) toRepository(c *Client) *Repository {
+	repo := &Repository{
+		ID:              r.DatabaseID,
+		NodeID:          r.ID,
+		Name:            r.Name,
+		Fullname:        r.NameWithOwner,
+		Owner:           r.Owner.toUser(c),
+		Private:         r.IsPrivate,
+		HTMLURL:         r.URL,
+		Description:     r.Description,
+		Fork:            r.IsFork,
+		URL:             c.apiURL("repos/%s", r.NameWithOwner),
+		CloneURL:        r.URL + ".git",
+		MirrorURL:       r.MirrorURL,
+		ForksCount:      r.ForkCount,
+		StargazersCount: r.StargazerCount,
+		WatchersCount:   r.StargazerCount,
+		Size:            r.DiskUsage,
+		OpenIssuesCount: r.Issues.TotalCount + r.PullRequests.TotalCount,
+		IsTemplate:      r.IsTemplate,
+		HasIssues:       r.HasIssuesEnabled,
+		HasProjects:     r.HasProjectsEnabled,
+		HasWiki:         r.HasWikiEnabled,
+		Archived:        r.IsArchived,
+		Disabled:        r.IsDisabled,
+		Visibility:      strings.ToLower(r.Visibility),
+		PushedAt:        r.PushedAt,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+
+	if r.PrimaryLanguage != nil {
+		repo.Language = r.PrimaryLanguage.Name
+	}
+
+	if r.DefaultBranchRef != nil {
+		repo.DefaultBranch = r.DefaultBranchRef.Name
+	}
+
+	repo.Topics = make([]string, len(r.RepositoryTopics.Nodes))
+	for i, n := range r.RepositoryTopics.Nodes {
+		repo.Topics[i] = n.Topic.Name
+	}
+
+	switch r.ViewerPermission {
+	case "ADMIN":
+		repo.Permissions.Admin, repo.Permissions.Push, repo.Permissions.Pull = true, true, true
+	case "MAINTAIN", "WRITE":
+		repo.Permissions.Push, repo.Permissions.Pull = true, true
+	case "TRIAGE", "READ":
+		repo.Permissions.Pull = true
+	}
+
+	return repo
+}
@@ -0,0 +1,215 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchServer answers batched issue lookups for the issue numbers in
+// found and reports every other number as missing.
+func batchServer(t *testing.T, found map[int]bool, queries *[]map[string]any) *httptest.Server {
+	var mu sync.Mutex
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body graphQLRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Contains(t, body.Query, "fragment issueFields on Issue")
+
+		mu.Lock()
+		*queries = append(*queries, body.Variables)
+		mu.Unlock()
+
+		data := map[string]any{}
+		var errs []map[string]any
+
+		for i := 0; ; i++ {
+			alias := fmt.Sprintf("k%d", i)
+
+			number, ok := body.Variables[alias+"n"].(float64)
+			if !ok {
+				break
+			}
+
+			if !found[int(number)] {
+				data[alias] = map[string]any{"issue": nil}
+				errs = append(errs, map[string]any{
+					"type":    "NOT_FOUND",
+					"path":    []string{alias, "issue"},
+					"message": fmt.Sprintf("Could not resolve to an issue with the number of %d.", int(number)),
+				})
+
+				continue
+			}
+
+			data[alias] = map[string]any{"issue": map[string]any{
+				"databaseId": 1000 + number,
+				"number":     number,
+				"state":      "OPEN",
+				"title":      fmt.Sprintf("Issue %d", int(number)),
+			}}
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data, "errors": errs})
+	}))
+}
+
+func TestBatchLoader_Issues(t *testing.T) {
+	var queries []map[string]any
+
+	ts := batchServer(t, map[int]bool{1: true, 2: true, 3: true, 5: true}, &queries)
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	keys := []IssueKey{
+		{Owner: "o", Repo: "r", Number: 1},
+		{Owner: "o", Repo: "r", Number: 2},
+		{Owner: "o", Repo: "r", Number: 3},
+		{Owner: "o", Repo: "r", Number: 4},
+		{Owner: "o", Repo: "r", Number: 5},
+		{Owner: "o", Repo: "r", Number: 1},
+	}
+
+	issues, err := NewBatchLoader(client, 2, 2).Issues(context.Background(), keys)
+	require.NoError(t, err, "missing issues are not errors")
+
+	assert.Len(t, queries, 3, "five unique keys in chunks of two")
+	assert.Len(t, issues, 4)
+	assert.NotContains(t, issues, IssueKey{Owner: "o", Repo: "r", Number: 4})
+
+	issue := issues[IssueKey{Owner: "o", Repo: "r", Number: 5}]
+	require.NotNil(t, issue)
+	assert.Equal(t, int64(1005), issue.ID)
+	assert.Equal(t, "Issue 5", issue.Title)
+	assert.Equal(t, "open", issue.State)
+	assert.Equal(t, ts.URL+"/repos/o/r/issues/5", issue.URL)
+}
+
+func TestBatchLoader_NodeLimit(t *testing.T) {
+	var queries []map[string]any
+
+	ts := batchServer(t, map[int]bool{1: true, 2: true, 3: true}, &queries)
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	loader := NewBatchLoader(client, 100, 1)
+	loader.maxNodes = 2 * (1 + batchLabels + batchUsers)
+
+	keys := []IssueKey{{"o", "r", 1}, {"o", "r", 2}, {"o", "r", 3}}
+
+	issues, err := loader.Issues(context.Background(), keys)
+	require.NoError(t, err)
+	assert.Len(t, issues, 3)
+	assert.Len(t, queries, 2, "no query requests more nodes than the limit")
+}
+
+func TestBatchLoader_Errors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body graphQLRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		if body.Variables["k0l"] == "broken" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data": {"k0": {"login": "octocat", "databaseId": 583231, "name": "The Octocat",
+			"followers": {"totalCount": 20}, "createdAt": "2011-01-25T18:44:36Z"}}}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	users, err := NewBatchLoader(client, 1, 0).Users(context.Background(), []string{"octocat", "broken"})
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+
+	require.Len(t, users, 1, "results of successful queries are returned")
+	assert.Equal(t, int64(583231), users["octocat"].ID)
+	assert.Equal(t, "The Octocat", users["octocat"].Name)
+	assert.Equal(t, 20, users["octocat"].Followers)
+	assert.Equal(t, 2011, users["octocat"].CreatedAt.Year())
+}
+
+func TestBatchLoader_RepositoriesAndPullRequests(t *testing.T) {
+	repository := `{
+		"databaseId": 1296269, "name": "hello-world", "nameWithOwner": "octocat/hello-world",
+		"url": "https://github.com/octocat/hello-world", "visibility": "PUBLIC",
+		"stargazerCount": 80, "viewerPermission": "WRITE",
+		"owner": {"__typename": "User", "login": "octocat", "databaseId": 583231},
+		"primaryLanguage": {"name": "Go"}, "defaultBranchRef": {"name": "main"},
+		"issues": {"totalCount": 3}, "pullRequests": {"totalCount": 2},
+		"repositoryTopics": {"nodes": [{"topic": {"name": "api"}}]}
+	}`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body graphQLRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		if strings.Contains(body.Query, "pullRequest(number:") {
+			_, _ = fmt.Fprintf(w, `{"data": {"k0": {"pullRequest": {
+				"databaseId": 1, "number": 1347, "state": "MERGED", "activeLockReason": "TOO_HEATED",
+				"url": "https://github.com/octocat/hello-world/pull/1347",
+				"author": {"__typename": "Bot", "login": "dependabot", "databaseId": 49699333},
+				"assignees": {"nodes": [{"__typename": "User", "login": "hubot"}]},
+				"reviewRequests": {"nodes": [{"requestedReviewer": {"__typename": "User", "login": "monalisa"}}, {"requestedReviewer": {}}]},
+				"repository": %s
+			}}}}`, repository)
+
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"data": {"k0": %s}}`, repository)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	loader := NewBatchLoader(client, 0, 0)
+
+	repos, err := loader.Repositories(context.Background(), []RepositoryKey{{Owner: "octocat", Name: "hello-world"}})
+	require.NoError(t, err)
+
+	repo := repos[RepositoryKey{Owner: "octocat", Name: "hello-world"}]
+	require.NotNil(t, repo)
+	assert.Equal(t, "octocat/hello-world", repo.Fullname)
+	assert.Equal(t, "octocat", repo.Owner.Login)
+	assert.Equal(t, "public", repo.Visibility)
+	assert.Equal(t, "Go", repo.Language)
+	assert.Equal(t, "main", repo.DefaultBranch)
+	assert.Equal(t, 5, repo.OpenIssuesCount)
+	assert.Equal(t, []string{"api"}, repo.Topics)
+	assert.Equal(t, ts.URL+"/repos/octocat/hello-world", repo.URL)
+	assert.True(t, repo.Permissions.Push)
+	assert.False(t, repo.Permissions.Admin)
+
+	prs, err := loader.PullRequests(context.Background(), []IssueKey{{Owner: "octocat", Repo: "hello-world", Number: 1347}})
+	require.NoError(t, err)
+
+	pr := prs[IssueKey{Owner: "octocat", Repo: "hello-world", Number: 1347}]
+	require.NotNil(t, pr)
+	assert.Equal(t, "closed", pr.State)
+	assert.Equal(t, "too heated", pr.ActiveLockReason)
+	assert.Equal(t, "Bot", pr.User.Type)
+	assert.Equal(t, "hubot", pr.Assignee.Login)
+	require.Len(t, pr.RequestedReviewers, 1, "teams are skipped")
+	assert.Equal(t, "monalisa", pr.RequestedReviewers[0].Login)
+	assert.Equal(t, "https://github.com/octocat/hello-world/pull/1347.diff", pr.DiffURL)
+	assert.Equal(t, "hello-world", pr.Repository.Name)
+}
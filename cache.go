@@ -0,0 +1,270 @@
+package github
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CacheStore stores responses for conditional requests.
+// Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Get returns the entry stored under key and whether it was found
+	Get(key string) (*CacheEntry, bool)
+
+	// Set stores the entry under key, replacing any previous entry
+	Set(key string, entry *CacheEntry)
+}
+
+// CacheEntry represents a cached API response together with the
+// validators needed to revalidate it.
+type CacheEntry struct {
+	// ETag contains the value of the ETag response header
+	ETag string `json:"etag,omitempty"`
+
+	// LastModified contains the value of the Last-Modified response header
+	LastModified string `json:"last_modified,omitempty"`
+
+	// Header contains the headers of the cached response
+	Header http.Header `json:"header"`
+
+	// Body contains the raw body of the cached response
+	Body []byte `json:"body"`
+}
+
+// MemoryCache is an in-memory CacheStore that evicts the least
+// recently used entry once its capacity is reached.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache creates an in-memory LRU cache holding at most
+// capacity entries. A capacity of zero or less means no limit.
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry stored under key and marks it as recently used.
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	m.ll.MoveToFront(el)
+
+	return el.Value.(*memoryCacheItem).entry, true
+}
+
+// Set stores the entry under key, evicting the least recently used
+// entry if the cache is full.
+func (m *MemoryCache) Set(key string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		el.Value.(*memoryCacheItem).entry = entry
+		m.ll.MoveToFront(el)
+
+		return
+	}
+
+	m.items[key] = m.ll.PushFront(&memoryCacheItem{key: key, entry: entry})
+
+	if m.capacity > 0 && m.ll.Len() > m.capacity {
+		oldest := m.ll.Back()
+		m.ll.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.ll.Len()
+}
+
+// DiskCache is a CacheStore that keeps each entry as a JSON file
+// in a directory. Read and write failures are treated as cache misses.
+type DiskCache struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewDiskCache creates a disk cache rooted at dir, creating the
+// directory if it does not exist.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get reads the entry stored under key from disk.
+func (d *DiskCache) Get(key string) (*CacheEntry, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	entry := new(CacheEntry)
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// Set writes the entry under key to disk.
+func (d *DiskCache) Set(key string, entry *CacheEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(d.dir, "entry-*")
+	if err != nil {
+		return
+	}
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// cacheKey identifies a cacheable request. The credentials are hashed
+// into the key so responses are never shared between different users.
+func cacheKey(req *http.Request) string {
+	auth := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+
+	return req.URL.String() + " " + req.Header.Get("Accept") + " " + hex.EncodeToString(auth[:8])
+}
+
+// prepareCache looks up a cached entry for req and, if one exists,
+// turns req into a conditional request. Requests authenticated from a
+// token pool are not cached, because the token is only chosen for each
+// attempt and the key could not tell the users of the pool apart.
+func (c *Client) prepareCache(req *http.Request) (string, *CacheEntry) {
+	if c.cache == nil || c.tokenPool != nil || req.Method != http.MethodGet {
+		return "", nil
+	}
+
+	key := cacheKey(req)
+
+	entry, ok := c.cache.Get(key)
+	if !ok {
+		return key, nil
+	}
+
+	req.Header = req.Header.Clone()
+
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+
+	return key, entry
+}
+
+// applyCache serves the cached body on 304 Not Modified and stores
+// fresh responses that carry validators.
+func (c *Client) applyCache(key string, entry *CacheEntry, resp *Response) error {
+	if key == "" {
+		return nil
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		_ = resp.Body.Close()
+
+		for k, v := range entry.Header {
+			if resp.Header.Get(k) == "" {
+				resp.Header[k] = v
+			}
+		}
+
+		resp.StatusCode = http.StatusOK
+		resp.Status = fmt.Sprintf("%d %s", http.StatusOK, http.StatusText(http.StatusOK))
+		resp.Body = io.NopCloser(bytes.NewReader(entry.Body))
+		resp.FromCache = true
+
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.cache.Set(key, &CacheEntry{
+		ETag:         etag,
+		LastModified: lastModified,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+	})
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache_Evicts(t *testing.T) {
+	cache := NewMemoryCache(2)
+
+	cache.Set("a", &CacheEntry{ETag: "a"})
+	cache.Set("b", &CacheEntry{ETag: "b"})
+
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+
+	cache.Set("c", &CacheEntry{ETag: "c"})
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "least recently used entry should be evicted")
+
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+
+	_, ok = cache.Get("c")
+	assert.True(t, ok)
+
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestDiskCache_RoundTrip(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir())
+	require.NoError(t, err)
+
+	_, ok := cache.Get("missing")
+	assert.False(t, ok)
+
+	entry := &CacheEntry{
+		ETag:         `"abc"`,
+		LastModified: "Wed, 21 Oct 2015 07:28:00 GMT",
+		Header:       http.Header{"Content-Type": []string{"application/json"}},
+		Body:         []byte(`{"id": 1}`),
+	}
+	cache.Set("key", entry)
+
+	got, ok := cache.Get("key")
+	require.True(t, ok)
+	assert.Equal(t, entry, got)
+}
+
+func TestDo_ConditionalCache(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		cond   string
+	}{
+		{
+			name:   "ETag",
+			header: "ETag",
+			value:  `"644b5b0155e6404a9cc4bd9d8b1ae730"`,
+			cond:   "If-None-Match",
+		},
+		{
+			name:   "Last-Modified",
+			header: "Last-Modified",
+			value:  "Wed, 21 Oct 2015 07:28:00 GMT",
+			cond:   "If-Modified-Since",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			requests := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Header().Set("X-RateLimit-Remaining", "4999")
+
+				if r.Header.Get(tt.cond) == tt.value {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+
+				w.Header().Set(tt.header, tt.value)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id": 1, "login": "octocat"}`))
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(WithBaseURL(ts.URL), WithCache(NewMemoryCache(10)))
+			require.NoError(t, err)
+
+			user, resp, err := client.Users.Get(context.Background(), "octocat")
+			require.NoError(t, err)
+			assert.False(t, resp.FromCache)
+			assert.Equal(t, "octocat", user.Login)
+
+			user, resp, err = client.Users.Get(context.Background(), "octocat")
+			require.NoError(t, err)
+			assert.True(t, resp.FromCache)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "200 OK", resp.Status)
+			assert.Equal(t, 4999, resp.Remaining)
+			assert.Equal(t, "octocat", user.Login)
+
+			assert.Equal(t, 2, requests)
+		})
+	}
+}
+
+func TestDo_CacheSkipsMutatingRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+
+		w.Header().Set("ETag", `"abc"`)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer ts.Close()
+
+	cache := NewMemoryCache(10)
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithCache(cache))
+	require.NoError(t, err)
+
+	_, _, err = client.Users.UpdateAuthenticated(context.Background(), UserUpdateRequest{Name: "octocat"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestCacheKey_SeparatesCredentials(t *testing.T) {
+	a, err := NewClient(WithToken("token-a"))
+	require.NoError(t, err)
+
+	b, err := NewClient(WithToken("token-b"))
+	require.NoError(t, err)
+
+	reqA, err := a.NewRequest(http.MethodGet, "user", nil)
+	require.NoError(t, err)
+
+	reqB, err := b.NewRequest(http.MethodGet, "user", nil)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, cacheKey(reqA), cacheKey(reqB))
+}
+
+func TestDo_CacheSkipsTokenPool(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"), "responses of one pooled token must not be revalidated with another")
+
+		w.Header().Set("ETag", `"abc"`)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer ts.Close()
+
+	cache := NewMemoryCache(10)
+
+	client, err := NewClient(
+		WithBaseURL(ts.URL),
+		WithTokenPool(NewTokenPool("token-a", "token-b")),
+		WithCache(cache),
+	)
+	require.NoError(t, err)
+
+	for range 2 {
+		_, resp, err := client.Users.Get(context.Background(), "octocat")
+		require.NoError(t, err)
+		assert.False(t, resp.FromCache)
+	}
+
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestDo_CacheAfterTokenRefresh(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			assert.Empty(t, r.Header.Get("If-None-Match"))
+			http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+
+			return
+		}
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"id": 1, "login": "octocat"}`))
+	}))
+	defer ts.Close()
+
+	cache := NewMemoryCache(10)
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithTokenSource(&rotatingTokenSource{}), WithCache(cache))
+	require.NoError(t, err)
+
+	_, resp, err := client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+	assert.False(t, resp.FromCache)
+
+	user, resp, err := client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+	assert.True(t, resp.FromCache, "the response is cached under the refreshed token")
+	assert.Equal(t, "octocat", user.Login)
+	assert.Equal(t, 1, cache.Len())
+}
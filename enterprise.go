@@ -0,0 +1,144 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const enterpriseVersionHeader = "X-GitHub-Enterprise-Version"
+
+// NewEnterpriseClient creates a client for a GitHub Enterprise Server
+// instance. The base and upload URLs may be given as the bare address of
+// the instance, such as https://github.example.com, in which case the
+// /api/v3/ and /api/uploads/ paths are appended. The GraphQL endpoint is
+// derived from the base URL. An empty upload URL defaults to the
+// /api/uploads/ path of the instance the base URL points to.
+func NewEnterpriseClient(baseURL, uploadURL string, opts ...option) (*Client, error) {
+	return NewClient(append([]option{WithEnterpriseURLs(baseURL, uploadURL)}, opts...)...)
+}
+
+// WithEnterpriseURLs configures the client to talk to a GitHub Enterprise
+// Server instance, normalizing the REST, upload and GraphQL endpoints like
+// NewEnterpriseClient.
+func WithEnterpriseURLs(baseURL, uploadURL string) option {
+	return func(c *Client) error {
+		base, err := parseEndpoint(baseURL, "api/v3/")
+		if err != nil {
+			return fmt.Errorf("failed to parse base URL %s: %w", baseURL, err)
+		}
+
+		if uploadURL == "" {
+			uploadURL = instanceRoot(base).String()
+		}
+
+		upload, err := parseEndpoint(uploadURL, "api/uploads/")
+		if err != nil {
+			return fmt.Errorf("failed to parse upload URL %s: %w", uploadURL, err)
+		}
+
+		c.baseURL = base
+		c.uploadURL = upload
+		c.graphQLURL = graphQLEndpoint(base)
+
+		return nil
+	}
+}
+
+// withEndpoints configures the client to use the given, already
+// normalized endpoints.
+func withEndpoints(baseURL, uploadURL, graphQLURL *url.URL) option {
+	return func(c *Client) error {
+		c.baseURL = baseURL
+		c.uploadURL = uploadURL
+		c.graphQLURL = graphQLURL
+
+		return nil
+	}
+}
+
+// EnterpriseVersion returns the GitHub Enterprise Server version reported
+// by the last response, or an empty string if the client talks to
+// github.com or has not received a response yet.
+func (c *Client) EnterpriseVersion() string {
+	version, _ := c.enterpriseVersion.Load().(string)
+
+	return version
+}
+
+// parseEndpoint parses an absolute endpoint URL and makes sure its path
+// ends with a slash, so that relative paths resolve below it. When the
+// URL points to the root of an Enterprise Server instance rather than to
+// an API host, suffix is appended to its path.
+func parseEndpoint(rawURL, suffix string) (*url.URL, error) {
+	u, err := parseBaseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAPIHost(u.Host) || strings.HasSuffix(u.Path, "/"+suffix) {
+		return u, nil
+	}
+
+	u.Path += suffix
+
+	return u, nil
+}
+
+// parseBaseURL parses an absolute URL and adds a trailing slash to its
+// path. Without it, url.URL.Parse would replace the last path segment
+// instead of appending to it.
+func parseBaseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("URL must be absolute")
+	}
+
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("URL must not have a query or fragment")
+	}
+
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+		u.RawPath = ""
+	}
+
+	return u, nil
+}
+
+// isAPIHost reports whether host serves the API at its root, like
+// api.github.com or the api subdomain of a GHE.com tenant.
+func isAPIHost(host string) bool {
+	return strings.HasPrefix(host, "api.") || strings.Contains(host, ".api.") ||
+		strings.HasPrefix(host, "uploads.")
+}
+
+// instanceRoot returns the root of the Enterprise Server instance that
+// serves the REST endpoint base, which is base without its api/v3/ path.
+func instanceRoot(base *url.URL) *url.URL {
+	u := *base
+	u.Path = strings.TrimSuffix(base.Path, "api/v3/")
+	u.RawPath = ""
+
+	return &u
+}
+
+// graphQLEndpoint returns the GraphQL endpoint that belongs to the REST
+// endpoint base: /api/graphql for /api/v3/ and graphql below any other.
+func graphQLEndpoint(base *url.URL) *url.URL {
+	if strings.HasSuffix(base.Path, "/api/v3/") {
+		u := *base
+		u.Path = strings.TrimSuffix(base.Path, "v3/") + "graphql"
+		u.RawPath = ""
+
+		return &u
+	}
+
+	u, _ := base.Parse("graphql")
+
+	return u
+}
@@ -0,0 +1,154 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEnterpriseClient_URLs(t *testing.T) {
+	tests := []struct {
+		name            string
+		baseURL         string
+		uploadURL       string
+		expectedBase    string
+		expectedUpload  string
+		expectedGraphQL string
+		expectError     bool
+	}{
+		{
+			name:            "bare host",
+			baseURL:         "https://github.example.com",
+			uploadURL:       "https://github.example.com",
+			expectedBase:    "https://github.example.com/api/v3/",
+			expectedUpload:  "https://github.example.com/api/uploads/",
+			expectedGraphQL: "https://github.example.com/api/graphql",
+		},
+		{
+			name:            "full paths without trailing slash",
+			baseURL:         "https://github.example.com/api/v3",
+			uploadURL:       "https://github.example.com/api/uploads",
+			expectedBase:    "https://github.example.com/api/v3/",
+			expectedUpload:  "https://github.example.com/api/uploads/",
+			expectedGraphQL: "https://github.example.com/api/graphql",
+		},
+		{
+			name:            "full paths",
+			baseURL:         "https://github.example.com/api/v3/",
+			uploadURL:       "https://github.example.com/api/uploads/",
+			expectedBase:    "https://github.example.com/api/v3/",
+			expectedUpload:  "https://github.example.com/api/uploads/",
+			expectedGraphQL: "https://github.example.com/api/graphql",
+		},
+		{
+			name:            "REST path without upload URL",
+			baseURL:         "https://github.example.com/api/v3",
+			expectedBase:    "https://github.example.com/api/v3/",
+			expectedUpload:  "https://github.example.com/api/uploads/",
+			expectedGraphQL: "https://github.example.com/api/graphql",
+		},
+		{
+			name:            "REST path with trailing slash without upload URL",
+			baseURL:         "https://github.example.com/api/v3/",
+			expectedBase:    "https://github.example.com/api/v3/",
+			expectedUpload:  "https://github.example.com/api/uploads/",
+			expectedGraphQL: "https://github.example.com/api/graphql",
+		},
+		{
+			name:            "instance below a path prefix",
+			baseURL:         "https://example.com/github/",
+			expectedBase:    "https://example.com/github/api/v3/",
+			expectedUpload:  "https://example.com/github/api/uploads/",
+			expectedGraphQL: "https://example.com/github/api/graphql",
+		},
+		{
+			name:            "api subdomain",
+			baseURL:         "https://api.tenant.ghe.com",
+			uploadURL:       "https://uploads.tenant.ghe.com",
+			expectedBase:    "https://api.tenant.ghe.com/",
+			expectedUpload:  "https://uploads.tenant.ghe.com/",
+			expectedGraphQL: "https://api.tenant.ghe.com/graphql",
+		},
+		{
+			name:        "relative URL",
+			baseURL:     "github.example.com",
+			expectError: true,
+		},
+		{
+			name:        "query",
+			baseURL:     "https://github.example.com/?x=1",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewEnterpriseClient(tt.baseURL, tt.uploadURL)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBase, client.baseURL.String())
+			assert.Equal(t, tt.expectedUpload, client.uploadURL.String())
+			assert.Equal(t, tt.expectedGraphQL, client.graphQLURL.String())
+		})
+	}
+}
+
+func TestWithBaseURL_TrailingSlash(t *testing.T) {
+	client, err := NewClient(WithBaseURL("https://example.com/github"))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(http.MethodGet, "users/octocat", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/github/users/octocat", req.URL.String())
+	assert.Equal(t, "https://example.com/github/graphql", client.graphQLURL.String())
+}
+
+func TestNewEnterpriseClient_Requests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-GitHub-Enterprise-Version", "3.14.2")
+
+		switch r.URL.Path {
+		case "/api/v3/users/octocat":
+			_, _ = w.Write([]byte(`{"login": "octocat"}`))
+		case "/api/uploads/repos/o/r/releases/1/assets":
+			assert.Equal(t, "text/plain", r.Header.Get("Content-Type"))
+			assert.Equal(t, "name=notes.txt", r.URL.RawQuery)
+
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, "release notes", string(body))
+
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewEnterpriseClient(ts.URL, ts.URL)
+	require.NoError(t, err)
+	assert.Empty(t, client.EnterpriseVersion())
+
+	user, resp, err := client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+	assert.Equal(t, "octocat", user.Login)
+	assert.Equal(t, "3.14.2", resp.EnterpriseVersion)
+	assert.Equal(t, "3.14.2", client.EnterpriseVersion())
+
+	req, err := client.NewUploadRequest("repos/o/r/releases/1/assets?name=notes.txt",
+		strings.NewReader("release notes"), 13, "text/plain")
+	require.NoError(t, err)
+
+	_, err = client.Do(context.Background(), req, nil)
+	require.NoError(t, err)
+}
@@ -2,8 +2,21 @@ package github
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
+)
+
+// Sentinel errors that can be matched with errors.Is against any
+// error returned by the client.
+var (
+	ErrNotFound          = errors.New("github: resource not found")
+	ErrRateLimited       = errors.New("github: rate limit exceeded")
+	ErrAbuseRateLimited  = errors.New("github: secondary rate limit exceeded")
+	ErrValidation        = errors.New("github: validation failed")
+	ErrTwoFactorRequired = errors.New("github: two-factor authentication required")
+	ErrConflict          = errors.New("github: conflict")
 )
 
 // APIError represents an error returned by the API.
@@ -36,6 +49,27 @@ type APIErrorDetail struct {
 
 	// Field specifies which field caused the error
 	Field string `json:"field,omitempty"`
+
+	// Message contains a human readable description of the error
+	Message string `json:"message,omitempty"`
+
+	// Value contains the rejected value, if the API reported it
+	Value any `json:"value,omitempty"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Some endpoints report errors as plain strings instead of objects,
+// in which case the string is stored in Message.
+func (d *APIErrorDetail) UnmarshalJSON(data []byte) error {
+	var msg string
+	if err := json.Unmarshal(data, &msg); err == nil {
+		*d = APIErrorDetail{Message: msg}
+		return nil
+	}
+
+	type detail APIErrorDetail
+
+	return json.Unmarshal(data, (*detail)(d))
 }
 
 func newAPIError(resp *http.Response) error {
@@ -54,3 +88,126 @@ func newAPIError(resp *http.Response) error {
 func (e *APIError) Error() string {
 	return fmt.Sprintf("API Error: %d - %s", e.StatusCode, e.Message)
 }
+
+// RateLimitError is returned when the primary rate limit is exhausted.
+type RateLimitError struct {
+	*APIError
+
+	// Rate contains the rate limit state reported with the error
+	Rate RateLimit
+
+	// ResetAt is the time at which the rate limit resets
+	ResetAt time.Time
+}
+
+// Unwrap returns the underlying APIError.
+func (e *RateLimitError) Unwrap() error { return e.APIError }
+
+// Is reports whether target is ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool { return target == ErrRateLimited }
+
+// AbuseRateLimitError is returned when a secondary (abuse) rate limit is hit.
+type AbuseRateLimitError struct {
+	*APIError
+
+	// RetryAfter is the wait requested by the Retry-After header,
+	// or nil if the header was absent
+	RetryAfter *time.Duration
+}
+
+// Unwrap returns the underlying APIError.
+func (e *AbuseRateLimitError) Unwrap() error { return e.APIError }
+
+// Is reports whether target is ErrAbuseRateLimited.
+func (e *AbuseRateLimitError) Is(target error) bool { return target == ErrAbuseRateLimited }
+
+// NotFoundError is returned when the requested resource does not exist
+// or is not visible to the authenticated user.
+type NotFoundError struct {
+	*APIError
+}
+
+// Unwrap returns the underlying APIError.
+func (e *NotFoundError) Unwrap() error { return e.APIError }
+
+// Is reports whether target is ErrNotFound.
+func (e *NotFoundError) Is(target error) bool { return target == ErrNotFound }
+
+// ValidationError is returned when the API rejects the request payload.
+// The individual problems are available in Errors.
+type ValidationError struct {
+	*APIError
+}
+
+// Unwrap returns the underlying APIError.
+func (e *ValidationError) Unwrap() error { return e.APIError }
+
+// Is reports whether target is ErrValidation.
+func (e *ValidationError) Is(target error) bool { return target == ErrValidation }
+
+// TwoFactorRequiredError is returned when the request must be repeated
+// with a one-time password.
+type TwoFactorRequiredError struct {
+	*APIError
+}
+
+// Unwrap returns the underlying APIError.
+func (e *TwoFactorRequiredError) Unwrap() error { return e.APIError }
+
+// Is reports whether target is ErrTwoFactorRequired.
+func (e *TwoFactorRequiredError) Is(target error) bool { return target == ErrTwoFactorRequired }
+
+// ConflictError is returned when the request conflicts with the current
+// state of the resource, for example merging an unmergeable pull request.
+type ConflictError struct {
+	*APIError
+}
+
+// Unwrap returns the underlying APIError.
+func (e *ConflictError) Unwrap() error { return e.APIError }
+
+// Is reports whether target is ErrConflict.
+func (e *ConflictError) Is(target error) bool { return target == ErrConflict }
+
+const otpHeader = "X-GitHub-OTP"
+
+// newError builds the typed error matching the failed response.
+func newError(resp *Response) error {
+	reason := resp.RetryReason
+	if reason == "" {
+		reason = checkRetry(resp)
+	}
+
+	apiErr := newAPIError(resp.Response).(*APIError)
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		if resp.Header.Get(otpHeader) != "" {
+			return &TwoFactorRequiredError{apiErr}
+		}
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		switch {
+		case reason == RetryReasonRateLimit && resp.Header.Get(rateRemainigHeader) != "":
+			return &RateLimitError{
+				APIError: apiErr,
+				Rate:     *resp.RateLimit,
+				ResetAt:  time.Unix(resp.Reset, 0),
+			}
+		case reason == RetryReasonSecondaryRateLimit:
+			abuseErr := &AbuseRateLimitError{APIError: apiErr}
+			if wait, ok := parseRetryAfter(resp); ok {
+				abuseErr.RetryAfter = &wait
+			}
+
+			return abuseErr
+		}
+	case http.StatusNotFound:
+		return &NotFoundError{apiErr}
+	case http.StatusConflict:
+		return &ConflictError{apiErr}
+	case http.StatusUnprocessableEntity:
+		return &ValidationError{apiErr}
+	}
+
+	return apiErr
+}
@@ -0,0 +1,171 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		headers    map[string]string
+		body       string
+		sentinel   error
+		check      func(t *testing.T, err error)
+	}{
+		{
+			name:       "not found",
+			statusCode: http.StatusNotFound,
+			body:       `{"message": "Not Found"}`,
+			sentinel:   ErrNotFound,
+			check: func(t *testing.T, err error) {
+				var e *NotFoundError
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, "Not Found", e.Message)
+			},
+		},
+		{
+			name:       "validation failed",
+			statusCode: http.StatusUnprocessableEntity,
+			body: `{
+				"message": "Validation Failed",
+				"errors": [
+					{"resource": "Issue", "field": "title", "code": "custom", "message": "title is too long", "value": "xxx"},
+					"labels must be an array"
+				]
+			}`,
+			sentinel: ErrValidation,
+			check: func(t *testing.T, err error) {
+				var e *ValidationError
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, []APIErrorDetail{
+					{Resource: "Issue", Field: "title", Code: "custom", Message: "title is too long", Value: "xxx"},
+					{Message: "labels must be an array"},
+				}, e.Errors)
+			},
+		},
+		{
+			name:       "conflict",
+			statusCode: http.StatusConflict,
+			body:       `{"message": "Head branch was modified"}`,
+			sentinel:   ErrConflict,
+			check: func(t *testing.T, err error) {
+				var e *ConflictError
+				require.ErrorAs(t, err, &e)
+			},
+		},
+		{
+			name:       "two-factor required",
+			statusCode: http.StatusUnauthorized,
+			headers:    map[string]string{"X-GitHub-OTP": "required; sms"},
+			body:       `{"message": "Must specify two-factor authentication OTP code."}`,
+			sentinel:   ErrTwoFactorRequired,
+			check: func(t *testing.T, err error) {
+				var e *TwoFactorRequiredError
+				require.ErrorAs(t, err, &e)
+			},
+		},
+		{
+			name:       "primary rate limit",
+			statusCode: http.StatusForbidden,
+			headers: map[string]string{
+				rateLimitHeader:    "5000",
+				rateRemainigHeader: "0",
+				rateResetHeader:    "1717029203",
+			},
+			body:     `{"message": "API rate limit exceeded"}`,
+			sentinel: ErrRateLimited,
+			check: func(t *testing.T, err error) {
+				var e *RateLimitError
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, 5000, e.Rate.Limit)
+				assert.Equal(t, time.Unix(1717029203, 0), e.ResetAt)
+			},
+		},
+		{
+			name:       "secondary rate limit",
+			statusCode: http.StatusForbidden,
+			headers: map[string]string{
+				rateRemainigHeader: "4000",
+				"Retry-After":      "30",
+			},
+			body:     `{"message": "You have exceeded a secondary rate limit."}`,
+			sentinel: ErrAbuseRateLimited,
+			check: func(t *testing.T, err error) {
+				var e *AbuseRateLimitError
+				require.ErrorAs(t, err, &e)
+				require.NotNil(t, e.RetryAfter)
+				assert.Equal(t, 30*time.Second, *e.RetryAfter)
+			},
+		},
+		{
+			name:       "plain forbidden",
+			statusCode: http.StatusForbidden,
+			headers:    map[string]string{rateRemainigHeader: "4000"},
+			body:       `{"message": "Resource not accessible by integration"}`,
+			check: func(t *testing.T, err error) {
+				_, ok := err.(*APIError)
+				assert.True(t, ok)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(WithBaseURL(ts.URL))
+			require.NoError(t, err)
+
+			_, resp, err := client.Issues.Get(context.Background(), "octocat", "Hello-World", 1)
+			require.Error(t, err)
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.statusCode, apiErr.StatusCode)
+
+			if tt.sentinel != nil {
+				assert.True(t, errors.Is(err, tt.sentinel))
+			}
+
+			tt.check(t, err)
+		})
+	}
+}
+
+func TestDo_MaxRetryWrapsTypedError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(rateRemainigHeader, "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithRateLimitRetry(true), WithRetryMax(1))
+	require.NoError(t, err)
+
+	_, _, err = client.Users.Get(context.Background(), "octocat")
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "max retry attempts")
+	assert.True(t, errors.Is(err, ErrRateLimited))
+}
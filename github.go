@@ -6,15 +6,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"slices"
+	"sync/atomic"
 	"time"
 )
 
 const (
-	defaultBaseURL      = "https://api.github.com"
+	defaultBaseURL      = "https://api.github.com/"
+	defaultUploadURL    = "https://uploads.github.com/"
 	defaultRetryWaitMin = time.Second
 	defaultRetryWaitMax = 60 * time.Second
 	defaultRetryMax     = 5
@@ -30,6 +31,8 @@ const (
 type Client struct {
 	client           *http.Client
 	baseURL          *url.URL
+	uploadURL        *url.URL
+	graphQLURL       *url.URL
 	token            string
 	userAgent        string
 	rateLimitRetry   bool
@@ -37,8 +40,26 @@ type Client struct {
 	retryMax         int
 	retryWaitMin     time.Duration
 	retryWaitMax     time.Duration
+	middleware       []Middleware
 	requestHook      func(*http.Request)
 	responseHook     func(*Response)
+	transport        http.RoundTripper
+	noRedirectClient *http.Client
+	logger           *slog.Logger
+	logOptions       LogOptions
+	instrumentation  Instrumentation
+	retryPolicy      RetryPolicy
+	tokenSource      TokenSource
+	tokenPool        *TokenPool
+	pacer            *Pacer
+	scheduler        *Scheduler
+	rateLimits       *rateLimitTracker
+	app              *appTokenSource
+	cache            CacheStore
+
+	// enterpriseVersion holds the last GitHub Enterprise Server version
+	// seen in a response
+	enterpriseVersion atomic.Value
 
 	// User service for user-related operations
 	Users *UsersService
@@ -57,6 +78,9 @@ type Client struct {
 
 	// RateLimit service for rate limiting operations
 	RateLimit *RateLimitService
+
+	// Apps service for GitHub App operations
+	Apps *AppsService
 }
 
 // NewClient creates a new API client with optional configuration.
@@ -65,13 +89,19 @@ type Client struct {
 // behavior.
 func NewClient(opts ...option) (*Client, error) {
 	baseURL, _ := url.Parse(defaultBaseURL)
+	uploadURL, _ := url.Parse(defaultUploadURL)
 	client := &Client{
 		client:       http.DefaultClient,
 		baseURL:      baseURL,
+		uploadURL:    uploadURL,
+		graphQLURL:   graphQLEndpoint(baseURL),
 		userAgent:    userAgent,
 		retryMax:     defaultRetryMax,
 		retryWaitMin: defaultRetryWaitMin,
 		retryWaitMax: defaultRetryWaitMax,
+		retryPolicy:  DefaultRetryPolicy,
+		rateLimits:   newRateLimitTracker(),
+		logOptions:   defaultLogOptions,
 	}
 
 	for _, opt := range opts {
@@ -80,20 +110,25 @@ func NewClient(opts ...option) (*Client, error) {
 		}
 	}
 
+	client.noRedirectClient = noRedirectClient(client.client)
+	client.transport = client.buildTransport()
+
 	client.Users = &UsersService{client}
 	client.Repositories = &RepositoriesService{client}
 	client.Issues = &IssuesService{client}
 	client.PullRequests = &PullRequestsService{client}
 	client.Search = &SearchService{client}
 	client.RateLimit = &RateLimitService{client}
+	client.Apps = &AppsService{client}
 
 	return client, nil
 }
 
 // NewRequest creates an API request with the specified HTTP method, path, and body.
 // This method constructs an HTTP request with proper headers including authentication,
-// content type, accept headers, and user agent.
-func (c *Client) NewRequest(method, path string, body any) (*http.Request, error) {
+// content type, accept headers, and user agent. Headers set by request
+// options override the defaults.
+func (c *Client) NewRequest(method, path string, body any, opts ...RequestOption) (*http.Request, error) {
 	var payload io.ReadWriter
 	if body != nil {
 		payload = &bytes.Buffer{}
@@ -114,7 +149,7 @@ func (c *Client) NewRequest(method, path string, body any) (*http.Request, error
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Accept", MediaTypeJSON)
 	req.Header.Set("X-Github-Api-Version", "2022-11-28")
 	req.Header.Set("User-Agent", c.userAgent)
 
@@ -126,6 +161,37 @@ func (c *Client) NewRequest(method, path string, body any) (*http.Request, error
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
 
+	newRequestConfig(opts).applyHeader(req)
+
+	return req, nil
+}
+
+// NewUploadRequest creates a request that uploads size bytes of the given
+// media type read from body to the specified path below the upload URL.
+func (c *Client) NewUploadRequest(path string, body io.Reader, size int64, mediaType string, opts ...RequestOption) (*http.Request, error) {
+	url, err := c.uploadURL.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL path %s: %w", path, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.ContentLength = size
+
+	req.Header.Set("Accept", MediaTypeJSON)
+	req.Header.Set("X-Github-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Content-Type", mediaType)
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	newRequestConfig(opts).applyHeader(req)
+
 	return req, nil
 }
 
@@ -133,22 +199,201 @@ func (c *Client) NewRequest(method, path string, body any) (*http.Request, error
 // This method executes the provided HTTP request and handles the response,
 // including automatic retry logic for rate limiting, error handling, and
 // JSON decoding of the response body into the provided target value.
-func (c *Client) Do(ctx context.Context, req *http.Request, v any) (*Response, error) {
-	req = req.WithContext(ctx)
+// If v is an io.Writer, the raw response body is copied into it instead.
+// Request options can disable retries or limit the duration of the call.
+func (c *Client) Do(ctx context.Context, req *http.Request, v any, opts ...RequestOption) (*Response, error) {
+	rc := newRequestConfig(opts)
+
+	if rc.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rc.timeout)
+		defer cancel()
+	}
+
+	return c.instrument(ctx, req, func(ctx context.Context) (*Response, error) {
+		return c.do(ctx, req, v, rc)
+	})
+}
+
+// instrument reports the start and end of send to the instrumentation of
+// the client, if any.
+func (c *Client) instrument(
+	ctx context.Context,
+	req *http.Request,
+	send func(ctx context.Context) (*Response, error),
+) (*Response, error) {
+	if c.instrumentation == nil {
+		return send(ctx)
+	}
 
+	info := c.requestInfo(req)
+	ctx = c.instrumentation.RequestStart(ctx, info)
+	start := time.Now()
+
+	resp, err := send(ctx)
+
+	result := RequestResult{RequestInfo: info, Duration: time.Since(start), Err: err}
+	if resp != nil && resp.Response != nil {
+		result.StatusCode = resp.StatusCode
+		result.FromCache = resp.FromCache
+	}
+
+	c.instrumentation.RequestEnd(ctx, result)
+
+	return resp, err
+}
+
+// do implements Do without instrumentation.
+func (c *Client) do(ctx context.Context, req *http.Request, v any, rc *requestConfig) (*Response, error) {
+	resp, err := c.send(ctx, req, rc, true)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode >= 400 {
+		err = newError(resp)
+		_ = resp.Body.Close()
+
+		return resp, err
+	}
+
+	if v != nil && resp.StatusCode != http.StatusNoContent {
+		if w, ok := v.(io.Writer); ok {
+			_, err = io.Copy(w, resp.Body)
+		} else {
+			err = json.NewDecoder(resp.Body).Decode(v)
+		}
+
+		if err != nil {
+			_ = resp.Body.Close()
+			return resp, err
+		}
+	}
+
+	_ = resp.Body.Close()
+
+	return resp, nil
+}
+
+// refreshOnBadCredentials resends the request once with a fresh token from
+// the token source when the API rejected the token it was sent with.
+func (c *Client) refreshOnBadCredentials(ctx context.Context, req *http.Request, rc *requestConfig, cache bool, resp *Response) (*Response, error) {
+	if !isBadCredentials(resp) {
+		return resp, nil
+	}
+
+	_ = resp.Body.Close()
+
+	if _, err := c.authorize(ctx, req, true); err != nil {
+		return resp, err
+	}
+
+	if err := rewindBody(req); err != nil {
+		return resp, err
+	}
+
+	return c.exchange(ctx, req, rc, cache)
+}
+
+// roundTrip sends the request, retrying it according to the client's
+// retry settings. On success the returned response body is left open.
+// Every attempt is admitted by the scheduler, whose slot is held until
+// the body is closed or the attempt is retried, so that requests waiting
+// to be retried do not count as in flight.
+func (c *Client) roundTrip(ctx context.Context, req *http.Request, rc *requestConfig) (*Response, error) {
 	var httpresp *http.Response
 	var err error
 	var resp *Response
 
+	var reason RetryReason
+
+	resource := c.requestResource(req)
+
+	var release func()
+	defer func() {
+		if release != nil {
+			release()
+		}
+	}()
+
+	retry := c.rateLimitRetry && !rc.noRetry
+
 	maxAtm := max(c.retryMax, 1)
+	if rc.noRetry {
+		maxAtm = 1
+	}
+
 	for attempt := range maxAtm {
-		if c.requestHook != nil {
-			c.requestHook(req)
+		if attempt > 0 {
+			if err := rewindBody(req); err != nil {
+				return resp, err
+			}
+		}
+
+		if c.scheduler != nil && !rc.anonymous {
+			release, err = c.scheduler.acquire(ctx, PriorityFromContext(ctx), resource)
+			if err != nil {
+				return resp, err
+			}
 		}
 
-		httpresp, err = c.client.Do(req)
+		poolIdx := -1
+		if c.tokenPool != nil && !rc.anonymous {
+			var token string
+			poolIdx, token = c.tokenPool.acquire(resource)
+
+			req.Header = req.Header.Clone()
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		if c.pacer != nil && !rc.anonymous {
+			if err := c.pacer.Wait(ctx, resource); err != nil {
+				return resp, err
+			}
+		}
+
+		attemptCtx := context.WithValue(ctx, attemptKey{}, Attempt{
+			Number:      attempt + 1,
+			Max:         maxAtm,
+			Resource:    resource,
+			RetryReason: reason,
+		})
+
+		start := time.Now()
+
+		httpresp, err = c.transport.RoundTrip(req.WithContext(attemptCtx))
 		if err != nil {
-			return nil, err
+			reason = RetryReasonNetworkError
+
+			if c.instrumentation != nil {
+				c.instrumentation.RequestAttempt(ctx, AttemptInfo{
+					RequestInfo: c.requestInfo(req),
+					Attempt:     attempt + 1,
+					Duration:    time.Since(start),
+					Err:         err,
+				})
+			}
+
+			if !retry || !c.retryPolicy(req, nil, err) {
+				return nil, err
+			}
+
+			if attempt >= maxAtm-1 {
+				return nil, fmt.Errorf("max retry attempts %d exceeded: %w", maxAtm, err)
+			}
+
+			if release != nil {
+				release()
+				release = nil
+			}
+
+			wait := exponentialBackoff(c.retryWaitMin, c.retryWaitMax, attempt)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+				continue
+			}
 		}
 
 		resp, err = newResponse(httpresp)
@@ -157,19 +402,62 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v any) (*Response, e
 			return resp, err
 		}
 
-		if c.responseHook != nil {
-			c.responseHook(resp)
+		resp.RetryReason = checkRetry(resp)
+		reason = resp.RetryReason
+
+		if resp.EnterpriseVersion != "" {
+			c.enterpriseVersion.Store(resp.EnterpriseVersion)
+		}
+
+		if c.instrumentation != nil {
+			info := AttemptInfo{
+				RequestInfo: c.requestInfo(req),
+				Attempt:     attempt + 1,
+				StatusCode:  resp.StatusCode,
+				Duration:    time.Since(start),
+				RetryReason: resp.RetryReason,
+			}
+
+			if resp.Header.Get(rateRemainigHeader) != "" {
+				rl := *resp.RateLimit
+				info.RateLimit = &rl
+				info.Resource = responseResource(resp, resource)
+			}
+
+			c.instrumentation.RequestAttempt(ctx, info)
+		}
+
+		if poolIdx != -1 {
+			c.tokenPool.update(poolIdx, resource, resp)
+		}
+
+		if resp.Header.Get(rateRemainigHeader) != "" {
+			c.rateLimits.update(responseResource(resp, resource), *resp.RateLimit)
 		}
 
-		if !checkRetry(resp) {
+		if resp.Header.Get(rateResetHeader) != "" {
+			if c.pacer != nil {
+				c.pacer.Update(responseResource(resp, resource), resp.RateLimit)
+			}
+
+			if c.scheduler != nil {
+				c.scheduler.update(responseResource(resp, resource), resp.RateLimit)
+			}
+		}
+
+		if resp.RetryReason == "" {
 			break
 		}
 
-		if !c.rateLimitRetry {
-			_ = resp.Body.Close()
+		if !retry || !c.retryPolicy(req, resp, nil) {
 			break
 		}
 
+		if release != nil {
+			release()
+			release = nil
+		}
+
 		if c.rateLimitHandler != nil {
 			err = c.rateLimitHandler(httpresp)
 			if err != nil {
@@ -178,13 +466,20 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v any) (*Response, e
 			}
 		}
 
-		_ = resp.Body.Close()
-
 		if attempt >= maxAtm-1 {
-			return resp, fmt.Errorf("max retry attempts %d exceeded", maxAtm)
+			err = fmt.Errorf("max retry attempts %d exceeded: %w", maxAtm, newError(resp))
+			_ = resp.Body.Close()
+
+			return resp, err
 		}
 
+		_ = resp.Body.Close()
+
 		wait := calcBackoff(c.retryWaitMin, c.retryWaitMax, attempt, resp)
+		if resp.RetryReason == RetryReasonRateLimit && c.tokenPool != nil && c.tokenPool.available(resource) {
+			wait = 0
+		}
+
 		select {
 		case <-ctx.Done():
 			return resp, ctx.Err()
@@ -193,52 +488,34 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v any) (*Response, e
 		}
 	}
 
-	if resp.StatusCode >= 400 {
-		_ = resp.Body.Close()
-		return resp, newAPIError(httpresp)
-	}
-
-	if v != nil && resp.StatusCode != http.StatusNoContent {
-		err = json.NewDecoder(resp.Body).Decode(v)
-		if err != nil {
-			_ = resp.Body.Close()
-			return resp, err
-		}
+	if release != nil {
+		resp.Body = &closeHookBody{ReadCloser: resp.Body, onClose: release}
+		release = nil
 	}
 
-	_ = resp.Body.Close()
-
 	return resp, nil
 }
 
-var serviceShuttedCodes = []int{
-	http.StatusForbidden,
-	http.StatusTooManyRequests,
-}
-
-var serviceUnavailableCodes = []int{
-	http.StatusInternalServerError,
-	http.StatusBadGateway,
-	http.StatusServiceUnavailable,
-}
-
-func checkRetry(resp *Response) bool {
-	if slices.Contains(serviceShuttedCodes, resp.StatusCode) && resp.Remaining == 0 {
-		return true
+// authorize sets the Authorization header from the token source, if one
+// is configured, and reports whether it did. Requests that already carry
+// credentials are left as is unless refresh is set, in which case a cached
+// token is invalidated first so the source provides a fresh one.
+func (c *Client) authorize(ctx context.Context, req *http.Request, refresh bool) (bool, error) {
+	if c.tokenSource == nil || (!refresh && req.Header.Get("Authorization") != "") {
+		return false, nil
 	}
 
-	return slices.Contains(serviceUnavailableCodes, resp.StatusCode)
-}
-
-func calcBackoff(minD time.Duration, maxD time.Duration, attempt int, resp *Response) time.Duration {
-	if resp.Reset != 0 {
-		resetTime := time.Unix(resp.Reset, 0)
+	if inv, ok := c.tokenSource.(TokenInvalidator); ok && refresh {
+		inv.Invalidate()
+	}
 
-		return time.Until(resetTime)
+	token, err := c.tokenSource.Token(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to obtain token: %w", err)
 	}
 
-	backoff := float64(minD) * math.Pow(2, float64(attempt))
-	wait := time.Duration(backoff)
+	req.Header = req.Header.Clone()
+	req.Header.Set("Authorization", "Bearer "+token)
 
-	return min(wait, maxD)
+	return true, nil
 }
@@ -99,7 +99,7 @@ func TestParseLinkHeader(t *testing.T) {
 		},
 		{
 			name:          "malformed URL",
-			linkHeader:    `<invalid-url>; rel="next"`,
+			linkHeader:    `<%zz>; rel="next"`,
 			expectError:   true,
 			expectedPrev:  0,
 			expectedFirst: 0,
@@ -137,6 +137,61 @@ func TestParseLinkHeader(t *testing.T) {
 	}
 }
 
+func TestParseLinkHeader_Cursor(t *testing.T) {
+	tests := []struct {
+		name              string
+		linkHeader        string
+		expectedNextToken string
+		expectedPrevToken string
+		expectedNextURL   string
+		expectedPrevURL   string
+		expectedNextPage  int
+	}{
+		{
+			name: "after and before cursors",
+			linkHeader: `<https://api.github.com/orgs/acme/audit-log?per_page=2&after=MS42OTQ>; rel="next", ` +
+				`<https://api.github.com/orgs/acme/audit-log?per_page=2&before=MS40MjA>; rel="prev"`,
+			expectedNextToken: "MS42OTQ",
+			expectedPrevToken: "MS40MjA",
+			expectedNextURL:   "https://api.github.com/orgs/acme/audit-log?per_page=2&after=MS42OTQ",
+			expectedPrevURL:   "https://api.github.com/orgs/acme/audit-log?per_page=2&before=MS40MjA",
+		},
+		{
+			name:            "since based pagination",
+			linkHeader:      `<https://api.github.com/users?since=46>; rel="next"`,
+			expectedNextURL: "https://api.github.com/users?since=46",
+		},
+		{
+			name:             "page based pagination keeps raw URL",
+			linkHeader:       `<https://api.github.com/repositories/123/issues?page=2>; rel="next"`,
+			expectedNextURL:  "https://api.github.com/repositories/123/issues?page=2",
+			expectedNextPage: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &Response{
+				Response: &http.Response{
+					Header: make(http.Header),
+				},
+			}
+			resp.Header.Set("Link", tt.linkHeader)
+
+			err := populatePagination(resp)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedNextToken, resp.NextPageToken)
+			assert.Equal(t, tt.expectedPrevToken, resp.PrevPageToken)
+			assert.Equal(t, tt.expectedNextURL, resp.NextURL)
+			assert.Equal(t, tt.expectedPrevURL, resp.PrevURL)
+			assert.Equal(t, tt.expectedNextPage, resp.NextPage)
+		})
+	}
+}
+
 func TestBuildErrorResponse(t *testing.T) {
 	cases := []struct {
 		name             string
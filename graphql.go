@@ -0,0 +1,236 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// GraphQLError is returned by Client.GraphQL when the response reports
+// errors. The data that could be resolved is still decoded, so a
+// GraphQLError may accompany a partial result.
+type GraphQLError struct {
+	// Errors contains the errors reported in the response
+	Errors []GraphQLErrorDetail
+}
+
+// GraphQLErrorDetail describes a single error of a GraphQL response.
+type GraphQLErrorDetail struct {
+	// Message contains a human readable description of the error
+	Message string `json:"message"`
+
+	// Type classifies the error, for example NOT_FOUND or RATE_LIMITED
+	Type string `json:"type,omitempty"`
+
+	// Path is the path of the response field that failed, made of
+	// field names (strings) and list indices (float64)
+	Path []any `json:"path,omitempty"`
+
+	// Locations are the positions in the query the error refers to
+	Locations []GraphQLLocation `json:"locations,omitempty"`
+
+	// Extensions contains additional information about the error
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// GraphQLLocation is a position in a GraphQL query.
+type GraphQLLocation struct {
+	// Line is the line number, starting at 1
+	Line int `json:"line"`
+
+	// Column is the column number, starting at 1
+	Column int `json:"column"`
+}
+
+func (e *GraphQLError) Error() string {
+	if len(e.Errors) == 0 {
+		return "GraphQL Error"
+	}
+
+	msg := fmt.Sprintf("GraphQL Error: %s", e.Errors[0].Error())
+	if len(e.Errors) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(e.Errors)-1)
+	}
+
+	return msg
+}
+
+// Is reports whether target is ErrNotFound or ErrRateLimited and one of
+// the errors has the matching type.
+func (e *GraphQLError) Is(target error) bool {
+	var typ string
+
+	switch target {
+	case ErrNotFound:
+		typ = "NOT_FOUND"
+	case ErrRateLimited:
+		typ = "RATE_LIMITED"
+	default:
+		return false
+	}
+
+	for _, d := range e.Errors {
+		if d.Type == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d GraphQLErrorDetail) Error() string {
+	if len(d.Path) == 0 {
+		return d.Message
+	}
+
+	path := make([]string, len(d.Path))
+	for i, p := range d.Path {
+		path[i] = fmt.Sprint(p)
+	}
+
+	return fmt.Sprintf("%s: %s", strings.Join(path, "."), d.Message)
+}
+
+// graphQLMutation matches the start of a mutation or subscription
+// operation in a GraphQL document.
+var graphQLMutation = regexp.MustCompile(`(?m)^[\s,]*(mutation|subscription)\b`)
+
+// isGraphQLQuery reports whether the GraphQL document only contains
+// queries, which do not change any data.
+func isGraphQLQuery(document string) bool {
+	return !graphQLMutation.MatchString(document)
+}
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage      `json:"data"`
+	Errors []GraphQLErrorDetail `json:"errors"`
+}
+
+// GraphQL sends a GraphQL query or mutation with the given variables to
+// the GraphQL endpoint and decodes the data of the response into v.
+// The request is authenticated, retried and passed through the middleware
+// like REST requests, and counts against the graphql rate limit resource.
+// Queries are retried on server and network errors like GET requests,
+// mutations only when they were rate limited.
+// If the response reports errors, they are returned as a *GraphQLError
+// after decoding the partial data.
+// GitHub API docs: https://docs.github.com/en/graphql/guides/forming-calls-with-graphql
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, v any, opts ...RequestOption) (*Response, error) {
+	body := graphQLRequest{Query: query, Variables: variables}
+
+	req, err := c.NewRequest(http.MethodPost, c.graphQLURL.String(), body, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if isGraphQLQuery(query) {
+		// queries are read-only, so failed attempts are safe to repeat
+		req.Header["Idempotency-Key"] = nil
+	}
+
+	var result graphQLResponse
+
+	resp, err := c.Do(ctx, req, &result, opts...)
+	if err != nil {
+		return resp, err
+	}
+
+	if v != nil && len(result.Data) > 0 && string(result.Data) != "null" {
+		if err := json.Unmarshal(result.Data, v); err != nil {
+			return resp, fmt.Errorf("failed to decode GraphQL data: %w", err)
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		return resp, &GraphQLError{Errors: result.Errors}
+	}
+
+	return resp, nil
+}
+
+// apiPath returns the path of req relative to the base URL. Requests to
+// the GraphQL endpoint, which lies outside the base URL on Enterprise
+// Server, have the path graphql.
+func (c *Client) apiPath(req *http.Request) string {
+	if req.URL.Host == c.graphQLURL.Host && req.URL.Path == c.graphQLURL.Path {
+		return "graphql"
+	}
+
+	return strings.TrimPrefix(req.URL.Path, c.baseURL.Path)
+}
+
+// PageInfo describes the position of a page within a GraphQL connection.
+type PageInfo struct {
+	HasNextPage     bool   `json:"hasNextPage"`
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+	StartCursor     string `json:"startCursor"`
+	EndCursor       string `json:"endCursor"`
+}
+
+// Connection is a page of a GraphQL connection, as selected by
+// nodes { ... } pageInfo { hasNextPage endCursor }.
+type Connection[T any] struct {
+	Nodes      []T      `json:"nodes"`
+	PageInfo   PageInfo `json:"pageInfo"`
+	TotalCount int      `json:"totalCount"`
+}
+
+// GraphQLCursor is the name of the query variable that GraphQLAll sets
+// to the end cursor of the previous page.
+const GraphQLCursor = "cursor"
+
+// GraphQLAll returns an iterator over every node of a GraphQL connection.
+// The query must declare a $cursor: String variable and pass it as the
+// after argument of the connection. Each page is decoded into a new Q and
+// conn selects the connection from it. Iteration stops after the last
+// page, on the first error, or when the context is cancelled.
+func GraphQLAll[Q, T any](ctx context.Context, c *Client, query string, variables map[string]any, conn func(*Q) *Connection[T], opts ...RequestOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		vars := maps.Clone(variables)
+		if vars == nil {
+			vars = map[string]any{}
+		}
+
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			var data Q
+			if _, err := c.GraphQL(ctx, query, vars, &data, opts...); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			page := conn(&data)
+			if page == nil {
+				return
+			}
+
+			for _, node := range page.Nodes {
+				if !yield(node, nil) {
+					return
+				}
+			}
+
+			if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" {
+				return
+			}
+
+			vars[GraphQLCursor] = page.PageInfo.EndCursor
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/graphql", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		var body graphQLRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "query($login: String!) { user(login: $login) { name } }", body.Query)
+		assert.Equal(t, map[string]any{"login": "octocat"}, body.Variables)
+
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4990")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.Header().Set("X-RateLimit-Resource", "graphql")
+		_, _ = w.Write([]byte(`{"data": {"user": {"name": "The Octocat"}}}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithToken("test-token"))
+	require.NoError(t, err)
+
+	var data struct {
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+	}
+
+	resp, err := client.GraphQL(context.Background(),
+		"query($login: String!) { user(login: $login) { name } }",
+		map[string]any{"login": "octocat"}, &data)
+	require.NoError(t, err)
+	assert.Equal(t, "The Octocat", data.User.Name)
+	assert.Equal(t, ResourceGraphQL, resp.Resource)
+	assert.Equal(t, 4990, client.RateLimits()[ResourceGraphQL].Remaining)
+	assert.NotContains(t, client.RateLimits(), ResourceCore)
+}
+
+func TestGraphQL_Errors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"data": {"a": {"name": "found"}, "b": null},
+			"errors": [
+				{
+					"type": "NOT_FOUND",
+					"path": ["b"],
+					"locations": [{"line": 1, "column": 30}],
+					"message": "Could not resolve to a Repository with the name 'o/missing'."
+				},
+				{"message": "Something else went wrong"}
+			]
+		}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	var data struct {
+		A *struct {
+			Name string `json:"name"`
+		} `json:"a"`
+		B *struct{} `json:"b"`
+	}
+
+	_, err = client.GraphQL(context.Background(), "query { ... }", nil, &data)
+	require.Error(t, err)
+	require.NotNil(t, data.A, "partial data is decoded")
+	assert.Equal(t, "found", data.A.Name)
+	assert.Nil(t, data.B)
+
+	var gqlErr *GraphQLError
+	require.ErrorAs(t, err, &gqlErr)
+	require.Len(t, gqlErr.Errors, 2)
+	assert.Equal(t, []any{"b"}, gqlErr.Errors[0].Path)
+	assert.Equal(t, []GraphQLLocation{{Line: 1, Column: 30}}, gqlErr.Errors[0].Locations)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, "GraphQL Error: b: Could not resolve to a Repository with the name 'o/missing'. (and 1 more)", err.Error())
+}
+
+func TestGraphQL_HTTPError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message": "Bad credentials"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	_, err = client.GraphQL(context.Background(), "query { viewer { login } }", nil, nil)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
+
+func TestGraphQL_RetriesQueries(t *testing.T) {
+	tests := []struct {
+		name             string
+		query            string
+		expectedAttempts int32
+		expectError      bool
+	}{
+		{
+			name:             "query",
+			query:            "query { viewer { login } }",
+			expectedAttempts: 2,
+		},
+		{
+			name:             "query shorthand",
+			query:            "{ viewer { login } }",
+			expectedAttempts: 2,
+		},
+		{
+			name:             "mutation",
+			query:            "mutation($id: ID!) {\n  addStar(input: {starrableId: $id}) { clientMutationId }\n}",
+			expectedAttempts: 1,
+			expectError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Empty(t, r.Header.Get("Idempotency-Key"), "the marker is never sent")
+
+				if attempts.Add(1) == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+
+				_, _ = w.Write([]byte(`{"data": {"viewer": {"login": "octocat"}}}`))
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(
+				WithBaseURL(ts.URL),
+				WithRateLimitRetry(true),
+				WithRetryWaitMin(time.Millisecond),
+				WithRetryWaitMax(time.Millisecond),
+			)
+			require.NoError(t, err)
+
+			_, err = client.GraphQL(context.Background(), tt.query, nil, nil)
+			if tt.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectedAttempts, attempts.Load())
+		})
+	}
+}
+
+func TestGraphQL_Enterprise(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/graphql", r.URL.Path)
+		_, _ = w.Write([]byte(`{"data": {}}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewEnterpriseClient(ts.URL, "")
+	require.NoError(t, err)
+
+	_, err = client.GraphQL(context.Background(), "query { viewer { login } }", nil, nil)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/graphql", nil)
+	require.NoError(t, err)
+	assert.Equal(t, ResourceGraphQL, client.requestResource(req))
+}
+
+func TestGraphQLAll(t *testing.T) {
+	pages := []struct {
+		cursor string
+		body   string
+	}{
+		{
+			cursor: "",
+			body: `{"data": {"repository": {"issues": {
+				"nodes": [{"number": 1}, {"number": 2}],
+				"pageInfo": {"hasNextPage": true, "endCursor": "c2"}
+			}}}}`,
+		},
+		{
+			cursor: "c2",
+			body: `{"data": {"repository": {"issues": {
+				"nodes": [{"number": 3}],
+				"pageInfo": {"hasNextPage": false, "endCursor": "c3"}
+			}}}}`,
+		},
+	}
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body graphQLRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "r", body.Variables["name"])
+
+		page := pages[requests]
+		if page.cursor == "" {
+			assert.NotContains(t, body.Variables, GraphQLCursor)
+		} else {
+			assert.Equal(t, page.cursor, body.Variables[GraphQLCursor])
+		}
+
+		requests++
+
+		_, _ = w.Write([]byte(page.body))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	type issue struct {
+		Number int `json:"number"`
+	}
+
+	type query struct {
+		Repository struct {
+			Issues Connection[issue] `json:"issues"`
+		} `json:"repository"`
+	}
+
+	variables := map[string]any{"name": "r"}
+
+	var numbers []int
+	for node, err := range GraphQLAll(context.Background(), client, "query(...) { ... }", variables,
+		func(q *query) *Connection[issue] { return &q.Repository.Issues }) {
+		require.NoError(t, err)
+
+		numbers = append(numbers, node.Number)
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, numbers)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, map[string]any{"name": "r"}, variables, "the caller's variables are not modified")
+}
+
+func TestGraphQLAll_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors": [{"type": "RATE_LIMITED", "message": "API rate limit exceeded"}]}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	type query struct {
+		Viewer struct {
+			Repositories Connection[json.RawMessage] `json:"repositories"`
+		} `json:"viewer"`
+	}
+
+	var errs []error
+	for _, err := range GraphQLAll(context.Background(), client, "query { ... }", nil,
+		func(q *query) *Connection[json.RawMessage] { return &q.Viewer.Repositories }) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrRateLimited)
+}
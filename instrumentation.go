@@ -0,0 +1,190 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Instrumentation receives events about the requests sent by a client,
+// for example to record metrics or tracing spans. Its methods are called
+// synchronously from Client.Do and must be safe for concurrent use.
+type Instrumentation interface {
+	// RequestStart is called when Client.Do starts sending a request.
+	// The returned context is used for the rest of the request, so it
+	// may carry a span that RequestAttempt and RequestEnd pick up
+	RequestStart(ctx context.Context, info RequestInfo) context.Context
+
+	// RequestAttempt is called after every attempt of sending a request,
+	// including attempts that are retried
+	RequestAttempt(ctx context.Context, attempt AttemptInfo)
+
+	// RequestEnd is called when Client.Do returns
+	RequestEnd(ctx context.Context, result RequestResult)
+}
+
+// RequestInfo describes a request sent by a client.
+type RequestInfo struct {
+	// Method is the HTTP method of the request
+	Method string
+
+	// Route is the path of the request with its parameters replaced by
+	// placeholders, such as repos/{owner}/{repo}/issues/{id}
+	Route string
+
+	// Resource is the rate limit resource the request is expected to
+	// count against
+	Resource string
+}
+
+// AttemptInfo describes a single attempt of sending a request.
+type AttemptInfo struct {
+	RequestInfo
+
+	// Attempt is the number of the attempt, starting at 1
+	Attempt int
+
+	// StatusCode is the status code of the response, or zero if the
+	// attempt failed without a response
+	StatusCode int
+
+	// Duration is how long the attempt took
+	Duration time.Duration
+
+	// Err is the transport error of the attempt, if any
+	Err error
+
+	// RetryReason is the reason the response is considered retryable,
+	// or empty if it is not
+	RetryReason RetryReason
+
+	// RateLimit is the rate limit reported by the response, if any
+	RateLimit *RateLimit
+}
+
+// RequestResult describes the outcome of a request sent by a client.
+type RequestResult struct {
+	RequestInfo
+
+	// StatusCode is the status code of the final response, or zero if
+	// the request failed without a response
+	StatusCode int
+
+	// Duration is how long the request took, including retries
+	Duration time.Duration
+
+	// Err is the error returned by Client.Do, if any
+	Err error
+
+	// FromCache reports whether the body was served from the cache
+	FromCache bool
+}
+
+// requestInfo describes req for instrumentation.
+func (c *Client) requestInfo(req *http.Request) RequestInfo {
+	return RequestInfo{
+		Method:   req.Method,
+		Route:    routeTemplate(c.apiPath(req)),
+		Resource: c.requestResource(req),
+	}
+}
+
+// routeParams names the parameter that follows a collection segment
+// when the parameter is not numeric.
+var routeParams = map[string]string{
+	"assignees":     "{username}",
+	"blobs":         "{sha}",
+	"branches":      "{branch}",
+	"collaborators": "{username}",
+	"commits":       "{ref}",
+	"environments":  "{environment}",
+	"followers":     "{username}",
+	"following":     "{username}",
+	"labels":        "{name}",
+	"members":       "{username}",
+	"statuses":      "{sha}",
+	"tags":          "{tag}",
+	"tarball":       "{ref}",
+	"teams":         "{team_slug}",
+	"topics":        "{name}",
+	"trees":         "{sha}",
+	"workflows":     "{workflow_id}",
+	"zipball":       "{ref}",
+}
+
+// routeRest names the parameter that spans the rest of the path when it
+// follows a segment, like the file path of the contents API.
+var routeRest = map[string]string{
+	"compare":  "{basehead}",
+	"contents": "{path}",
+	"readme":   "{dir}",
+	"ref":      "{ref}",
+	"refs":     "{ref}",
+}
+
+// staticSegment matches the fixed segments of API paths. Any other
+// segment is a parameter, even if the route tables above do not know it,
+// so that values such as commit SHAs and tag names never become routes.
+var staticSegment = regexp.MustCompile(`^[a-z][a-z_-]*$`)
+
+// routeTemplate replaces the parameters of an API path with placeholders
+// so that metrics can be grouped by endpoint without unbounded cardinality.
+func routeTemplate(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case len(segments) >= 3 && segments[0] == "repos":
+		segments[1], segments[2] = "{owner}", "{repo}"
+	case len(segments) >= 2 && segments[0] == "users":
+		segments[1] = "{username}"
+	case len(segments) >= 2 && segments[0] == "orgs":
+		segments[1] = "{org}"
+	case len(segments) >= 2 && segments[0] == "gists" && segments[1] != "public" && segments[1] != "starred":
+		segments[1] = "{gist_id}"
+	}
+
+	for i := 1; i < len(segments); i++ {
+		prev, seg := segments[i-1], segments[i]
+
+		if isNumeric(seg) {
+			segments[i] = "{id}"
+			continue
+		}
+
+		if param, ok := routeRest[prev]; ok {
+			segments = append(segments[:i], param)
+			break
+		}
+
+		if strings.HasPrefix(seg, "{") {
+			continue
+		}
+
+		if param, ok := routeParams[prev]; ok {
+			segments[i] = param
+			continue
+		}
+
+		if !staticSegment.MatchString(seg) {
+			segments[i] = "{param}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,174 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingInstrumentation struct {
+	mu       sync.Mutex
+	events   []string
+	attempts []AttemptInfo
+	results  []RequestResult
+}
+
+type spanKey struct{}
+
+func (r *recordingInstrumentation) RequestStart(ctx context.Context, info RequestInfo) context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, "start "+info.Method+" "+info.Route)
+
+	return context.WithValue(ctx, spanKey{}, info.Route)
+}
+
+func (r *recordingInstrumentation) RequestAttempt(ctx context.Context, attempt AttemptInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, "attempt "+ctx.Value(spanKey{}).(string))
+	r.attempts = append(r.attempts, attempt)
+}
+
+func (r *recordingInstrumentation) RequestEnd(ctx context.Context, result RequestResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, "end "+ctx.Value(spanKey{}).(string))
+	r.results = append(r.results, result)
+}
+
+func TestRouteTemplate(t *testing.T) {
+	tests := map[string]string{
+		"/repos/octocat/hello/issues":                                                "repos/{owner}/{repo}/issues",
+		"repos/octocat/hello/issues/42/comments":                                     "repos/{owner}/{repo}/issues/{id}/comments",
+		"repos/octocat/issues/issues/comments":                                       "repos/{owner}/{repo}/issues/comments",
+		"repos/octocat/hello/contents/docs/README.md":                                "repos/{owner}/{repo}/contents/{path}",
+		"repos/octocat/hello/git/refs/heads/main":                                    "repos/{owner}/{repo}/git/refs/{ref}",
+		"repos/octocat/hello/branches/main/protection":                               "repos/{owner}/{repo}/branches/{branch}/protection",
+		"users/octocat/repos":                                                        "users/{username}/repos",
+		"user/following/octocat":                                                     "user/following/{username}",
+		"orgs/github/members/octocat":                                                "orgs/{org}/members/{username}",
+		"app/installations/123/access_tokens":                                        "app/installations/{id}/access_tokens",
+		"search/repositories":                                                        "search/repositories",
+		"repos/octocat/hello/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e":       "repos/{owner}/{repo}/commits/{ref}",
+		"repos/octocat/hello/git/trees/6dcb09b5b57875f334f61aebed695e2e4193db5e":     "repos/{owner}/{repo}/git/trees/{sha}",
+		"repos/octocat/hello/commits/6dcb09b5b57875f334f61aebed695e2e4193db5e/pulls": "repos/{owner}/{repo}/commits/{ref}/pulls",
+		"repos/octocat/hello/compare/main...feature/login":                           "repos/{owner}/{repo}/compare/{basehead}",
+		"repos/octocat/hello/releases/tags/v1.2.3":                                   "repos/{owner}/{repo}/releases/tags/{tag}",
+		"repos/octocat/hello/actions/secrets/public-key":                             "repos/{owner}/{repo}/actions/secrets/public-key",
+		"repos/octocat/hello/actions/secrets/NPM_TOKEN":                              "repos/{owner}/{repo}/actions/secrets/{param}",
+		"repos/octocat/hello/unknown/a1b2c3d":                                        "repos/{owner}/{repo}/unknown/{param}",
+		"gists/aa5a315d61ae9438b18d":                                                 "gists/{gist_id}",
+		"gists/starred":                                                              "gists/starred",
+		"users":                                                                      "users",
+	}
+
+	for path, expected := range tests {
+		t.Run(path, func(t *testing.T) {
+			assert.Equal(t, expected, routeTemplate(path))
+		})
+	}
+}
+
+func TestWithInstrumentation(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set(rateLimitHeader, "5000")
+		w.Header().Set(rateRemainigHeader, "4990")
+		w.Header().Set(rateResourceHeader, ResourceCore)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer ts.Close()
+
+	inst := &recordingInstrumentation{}
+
+	client, err := NewClient(
+		WithBaseURL(ts.URL+"/api/v3/"),
+		WithInstrumentation(inst),
+		WithRateLimitRetry(true),
+		WithRetryWaitMin(time.Millisecond),
+		WithRetryWaitMax(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.Issues.Get(context.Background(), "octocat", "hello", 7)
+	require.NoError(t, err)
+
+	route := "repos/{owner}/{repo}/issues/{id}"
+
+	assert.Equal(t, []string{"start GET " + route, "attempt " + route, "attempt " + route, "end " + route}, inst.events)
+
+	require.Len(t, inst.attempts, 2)
+	assert.Equal(t, 1, inst.attempts[0].Attempt)
+	assert.Equal(t, http.StatusServiceUnavailable, inst.attempts[0].StatusCode)
+	assert.Equal(t, RetryReasonServerError, inst.attempts[0].RetryReason)
+	assert.Equal(t, 2, inst.attempts[1].Attempt)
+	assert.Equal(t, &RateLimit{Limit: 5000, Remaining: 4990}, inst.attempts[1].RateLimit)
+
+	require.Len(t, inst.results, 1)
+	assert.Equal(t, http.StatusOK, inst.results[0].StatusCode)
+	assert.Equal(t, ResourceCore, inst.results[0].Resource)
+	assert.NoError(t, inst.results[0].Err)
+}
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics(0.1, 1)
+	ctx := context.Background()
+
+	info := RequestInfo{Method: http.MethodGet, Route: "repos/{owner}/{repo}/issues", Resource: ResourceCore}
+
+	m.RequestAttempt(ctx, AttemptInfo{RequestInfo: info, Attempt: 1, StatusCode: 502, RetryReason: RetryReasonServerError})
+	m.RequestAttempt(ctx, AttemptInfo{RequestInfo: info, Attempt: 2, StatusCode: 200, RateLimit: &RateLimit{Limit: 5000, Remaining: 4321}})
+	m.RequestEnd(ctx, RequestResult{RequestInfo: info, StatusCode: 200, Duration: 500 * time.Millisecond})
+	m.RequestEnd(ctx, RequestResult{RequestInfo: info, Duration: 50 * time.Millisecond})
+
+	ts := httptest.NewServer(m)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `# HELP github_client_requests_total Number of API requests.
+# TYPE github_client_requests_total counter
+github_client_requests_total{method="GET",route="repos/{owner}/{repo}/issues",status="200"} 1
+github_client_requests_total{method="GET",route="repos/{owner}/{repo}/issues",status="error"} 1
+# HELP github_client_request_duration_seconds Duration of API requests including retries.
+# TYPE github_client_request_duration_seconds histogram
+github_client_request_duration_seconds_bucket{method="GET",route="repos/{owner}/{repo}/issues",le="0.1"} 1
+github_client_request_duration_seconds_bucket{method="GET",route="repos/{owner}/{repo}/issues",le="1"} 2
+github_client_request_duration_seconds_bucket{method="GET",route="repos/{owner}/{repo}/issues",le="+Inf"} 2
+github_client_request_duration_seconds_sum{method="GET",route="repos/{owner}/{repo}/issues"} 0.55
+github_client_request_duration_seconds_count{method="GET",route="repos/{owner}/{repo}/issues"} 2
+# HELP github_client_retries_total Number of request attempts with a retryable response.
+# TYPE github_client_retries_total counter
+github_client_retries_total{method="GET",route="repos/{owner}/{repo}/issues",reason="server_error"} 1
+# HELP github_client_rate_limit_remaining Remaining rate limit quota.
+# TYPE github_client_rate_limit_remaining gauge
+github_client_rate_limit_remaining{resource="core"} 4321
+# HELP github_client_rate_limit_limit Rate limit quota.
+# TYPE github_client_rate_limit_limit gauge
+github_client_rate_limit_limit{resource="core"} 5000
+`, string(body))
+}
@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
 	"strings"
@@ -17,6 +18,7 @@ type IssuesService struct {
 // GitHub API docs: https://docs.github.com/en/rest/issues/labels
 type Label struct {
 	ID          int64  `json:"id"`
+	NodeID      string `json:"node_id"`
 	URL         string `json:"url"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
@@ -28,12 +30,15 @@ type Label struct {
 // GitHub API docs: https://docs.github.com/en/rest/issues/issues
 type Issue struct {
 	ID            int64      `json:"id"`
+	NodeID        string     `json:"node_id"`
 	URL           string     `json:"url"`
 	RepositoryURL string     `json:"repository_url"`
 	Number        int        `json:"number"`
 	State         string     `json:"state"`
 	Title         string     `json:"title"`
 	Body          string     `json:"body"`
+	BodyHTML      string     `json:"body_html"`
+	BodyText      string     `json:"body_text"`
 	Labels        []*Label   `json:"labels"`
 	User          *User      `json:"user"`
 	Assignee      *User      `json:"assignee"`
@@ -50,17 +55,17 @@ type Issue struct {
 // This method retrieves detailed information about a specific issue,
 // including its title, body, labels, assignees, and other metadata.
 // The issue number is the unique identifier within the repository.
-func (s *IssuesService) Get(ctx context.Context, owner string, repo string, issueNum int) (*Issue, *Response, error) {
+func (s *IssuesService) Get(ctx context.Context, owner string, repo string, issueNum int, reqOpts ...RequestOption) (*Issue, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/issues/%d", owner, repo, issueNum)
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	issue := new(Issue)
 
-	resp, err := s.client.Do(ctx, req, issue)
+	resp, err := s.client.Do(ctx, req, issue, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -89,17 +94,18 @@ func (s *IssuesService) Create(
 	owner string,
 	repo string,
 	body *IssueCreateRequest,
+	reqOpts ...RequestOption,
 ) (*Issue, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/issues", owner, repo)
 
-	req, err := s.client.NewRequest(http.MethodPost, path, body)
+	req, err := s.client.NewRequest(http.MethodPost, path, body, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	issue := new(Issue)
 	
-	resp, err := s.client.Do(ctx, req, issue)
+	resp, err := s.client.Do(ctx, req, issue, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -131,17 +137,18 @@ func (s *IssuesService) Update(
 	repo string,
 	issueNum int,
 	body *IssueUpdateRequest,
+	reqOpts ...RequestOption,
 ) (*Issue, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/issues/%d", owner, repo, issueNum)
 
-	req, err := s.client.NewRequest(http.MethodPatch, path, body)
+	req, err := s.client.NewRequest(http.MethodPatch, path, body, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	issue := new(Issue)
 
-	resp, err := s.client.Do(ctx, req, issue)
+	resp, err := s.client.Do(ctx, req, issue, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -165,15 +172,16 @@ func (s *IssuesService) Lock(
 	repo string,
 	issueNum int,
 	body *IssueLockRequest,
+	reqOpts ...RequestOption,
 ) (*Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/issues/%d/lock", owner, repo, issueNum)
 
-	req, err := s.client.NewRequest(http.MethodPut, path, body)
+	req, err := s.client.NewRequest(http.MethodPut, path, body, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(ctx, req, nil)
+	resp, err := s.client.Do(ctx, req, nil, reqOpts...)
 	if err != nil {
 		return resp, err
 	}
@@ -184,15 +192,15 @@ func (s *IssuesService) Lock(
 // Unlock unlocks a previously locked issue.
 // This method removes the lock from an issue, allowing all users
 // (including non-collaborators) to comment on it again.
-func (s *IssuesService) Unlock(ctx context.Context, owner string, repo string, issueNum int) (*Response, error) {
+func (s *IssuesService) Unlock(ctx context.Context, owner string, repo string, issueNum int, reqOpts ...RequestOption) (*Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/issues/%d/lock", owner, repo, issueNum)
 
-	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := s.client.NewRequest(http.MethodDelete, path, nil, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(ctx, req, nil)
+	resp, err := s.client.Do(ctx, req, nil, reqOpts...)
 	if err != nil {
 		return resp, err
 	}
@@ -221,7 +229,7 @@ type IssueListOptions struct {
 // You can filter and sort the results using various options such as
 // issue state, assignee, creator, labels, and creation date.
 // The results are returned in pages according to the pagination options.
-func (s *IssuesService) ListByRepo(ctx context.Context, owner string, repo string, opts *IssueListOptions) ([]*Issue, *Response, error) {
+func (s *IssuesService) ListByRepo(ctx context.Context, owner string, repo string, opts *IssueListOptions, reqOpts ...RequestOption) ([]*Issue, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/issues", owner, repo)
 
 	if opts != nil {
@@ -273,14 +281,14 @@ func (s *IssuesService) ListByRepo(ctx context.Context, owner string, repo strin
 		}
 	}
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	issues := new([]*Issue)
 
-	res, err := s.client.Do(ctx, req, issues)
+	res, err := s.client.Do(ctx, req, issues, reqOpts...)
 	if err != nil {
 		return nil, res, err
 	}
@@ -288,6 +296,24 @@ func (s *IssuesService) ListByRepo(ctx context.Context, owner string, repo strin
 	return *issues, res, nil
 }
 
+// ListByRepoAll returns an iterator over all issues in a repository.
+// It transparently follows pagination links, starting from the page
+// set in opts, and stops early once opts.MaxItems issues have been
+// yielded or the context is cancelled.
+func (s *IssuesService) ListByRepoAll(ctx context.Context, owner string, repo string, opts *IssueListOptions, reqOpts ...RequestOption) iter.Seq2[*Issue, error] {
+	return eachRange(func() iter.Seq2[*Issue, error] {
+		o := IssueListOptions{}
+		if opts != nil {
+			o = *opts
+		}
+
+		return paginate(ctx, o.ListOptions, func(ctx context.Context, lo *ListOptions) ([]*Issue, *Response, error) {
+			o.ListOptions = lo
+			return s.ListByRepo(ctx, owner, repo, &o, reqOpts...)
+		})
+	})
+}
+
 // IssueCommentRequest represents the request body for creating or updating an issue comment.
 // GitHub API docs: https://docs.github.com/en/rest/issues/comments
 type IssueCommentRequest struct {
@@ -298,8 +324,11 @@ type IssueCommentRequest struct {
 // GitHub API docs: https://docs.github.com/en/rest/issues/comments
 type IssueComment struct {
 	ID        int        `json:"id"`
+	NodeID    string     `json:"node_id"`
 	URL       string     `json:"url"`
 	Body      string     `json:"body"`
+	BodyHTML  string     `json:"body_html"`
+	BodyText  string     `json:"body_text"`
 	User      *User      `json:"user"`
 	CreatedAt *Timestamp `json:"created_at"`
 	UpdatedAt *Timestamp `json:"updated_at"`
@@ -316,17 +345,18 @@ func (s *IssuesService) CreateComment(
 	repo string,
 	issueNum int,
 	body IssueCommentRequest,
+	reqOpts ...RequestOption,
 ) (*IssueComment, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, repo, issueNum)
 
-	req, err := s.client.NewRequest(http.MethodPost, path, body)
+	req, err := s.client.NewRequest(http.MethodPost, path, body, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	comment := new(IssueComment)
 
-	resp, err := s.client.Do(ctx, req, comment)
+	resp, err := s.client.Do(ctx, req, comment, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -353,6 +383,7 @@ func (s *IssuesService) ListCommentsByRepo(
 	owner string,
 	repo string,
 	opts *IssueCommentListOptions,
+	reqOpts ...RequestOption,
 ) ([]*IssueComment, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/issues/comments", owner, repo)
 
@@ -381,17 +412,41 @@ func (s *IssuesService) ListCommentsByRepo(
 		}
 	}
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	comments := new([]*IssueComment)
 
-	res, err := s.client.Do(ctx, req, comments)
+	res, err := s.client.Do(ctx, req, comments, reqOpts...)
 	if err != nil {
 		return nil, res, err
 	}
 
 	return *comments, res, nil
 }
+
+// ListCommentsByRepoAll returns an iterator over all comments in a repository.
+// It transparently follows pagination links, starting from the page
+// set in opts, and stops early once opts.MaxItems comments have been
+// yielded or the context is cancelled.
+func (s *IssuesService) ListCommentsByRepoAll(
+	ctx context.Context,
+	owner string,
+	repo string,
+	opts *IssueCommentListOptions,
+	reqOpts ...RequestOption,
+) iter.Seq2[*IssueComment, error] {
+	return eachRange(func() iter.Seq2[*IssueComment, error] {
+		o := IssueCommentListOptions{}
+		if opts != nil {
+			o = *opts
+		}
+
+		return paginate(ctx, o.ListOptions, func(ctx context.Context, lo *ListOptions) ([]*IssueComment, *Response, error) {
+			o.ListOptions = lo
+			return s.ListCommentsByRepo(ctx, owner, repo, &o, reqOpts...)
+		})
+	})
+}
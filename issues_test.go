@@ -509,3 +509,21 @@ func TestIssuesService_ListCommentsByRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestIssuesService_ListCommentsByRepo_HTMLMediaType(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, MediaTypeHTML, r.Header.Get("Accept"))
+
+		_, _ = w.Write([]byte(`[{"id": 1, "body_html": "<p>hi</p>"}]`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	comments, _, err := client.Issues.ListCommentsByRepo(context.Background(), "o", "r", nil, WithAccept(MediaTypeHTML))
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "<p>hi</p>", comments[0].BodyHTML)
+	assert.Empty(t, comments[0].Body)
+}
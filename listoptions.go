@@ -15,6 +15,19 @@ type ListOptions struct {
 
 	// PerPage specifies the number of items per page.
 	PerPage int
+
+	// After specifies the cursor to list results after, for endpoints
+	// that use cursor-based pagination
+	After string
+
+	// Before specifies the cursor to list results before, for endpoints
+	// that use cursor-based pagination
+	Before string
+
+	// MaxItems limits the total number of items yielded by the
+	// auto-paginating iterators. Zero means no limit. It is not sent
+	// to the API.
+	MaxItems int
 }
 
 // Apply adds the pagination parameters from ListOptions to the provided URL values.
@@ -28,4 +41,12 @@ func (lo *ListOptions) Apply(v url.Values) {
 	if lo.PerPage != 0 {
 		v.Set("per_page", strconv.Itoa(lo.PerPage))
 	}
+
+	if lo.After != "" {
+		v.Set("after", lo.After)
+	}
+
+	if lo.Before != "" {
+		v.Set("before", lo.Before)
+	}
 }
@@ -0,0 +1,303 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	requestIDHeader = "X-GitHub-Request-Id"
+
+	redacted = "[REDACTED]"
+)
+
+// LogOptions configures the request logging enabled with WithLogger.
+type LogOptions struct {
+	// Level is the level of records for successful requests.
+	// Defaults to slog.LevelDebug if nil
+	Level slog.Leveler
+
+	// ErrorLevel is the level of records for failed requests, responses
+	// with a 4xx or 5xx status and transport errors.
+	// Defaults to slog.LevelWarn if nil
+	ErrorLevel slog.Leveler
+
+	// BodyLimit is the maximum number of bytes of request and response
+	// bodies to log. Only the beginning of a response body is read, so
+	// downloads keep streaming. Bodies are not logged if it is zero
+	BodyLimit int
+
+	// RedactFields lists additional JSON field and query parameter names
+	// whose values are redacted, on top of the built-in secret-like names
+	RedactFields []string
+}
+
+// defaultLogOptions are used when WithLogOptions is not given, and fill in
+// the levels it leaves nil.
+var defaultLogOptions = LogOptions{
+	Level:      slog.LevelDebug,
+	ErrorLevel: slog.LevelWarn,
+}
+
+// secretFieldNames are substrings of field names whose values are redacted.
+var secretFieldNames = []string{
+	"token",
+	"secret",
+	"password",
+	"passwd",
+	"private_key",
+	"authorization",
+	"credential",
+	"client_assertion",
+}
+
+// secretPatterns match credentials anywhere in logged text.
+var secretPatterns = []*regexp.Regexp{
+	// personal access, OAuth, user-to-server, server-to-server and refresh tokens
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{20,}\b`),
+	// fine-grained personal access tokens
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{20,}\b`),
+	// JWTs, such as the ones that authenticate GitHub Apps
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	// credentials following an authorization scheme
+	regexp.MustCompile(`(?i)\b(bearer|token|basic)\s+[A-Za-z0-9._~+/=-]{8,}`),
+}
+
+// jsonStringField matches a JSON field with a string value, including
+// a value cut off at the end of a partial body.
+var jsonStringField = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)"(?:[^"\\]|\\.?)*"?`)
+
+// loggingMiddleware logs every attempt of a request to logger.
+func loggingMiddleware(logger *slog.Logger, opts LogOptions) Middleware {
+	r := &redactor{fields: append(append([]string{}, secretFieldNames...), opts.RedactFields...)}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+
+			minLevel := min(opts.Level.Level(), opts.ErrorLevel.Level())
+			if !logger.Enabled(ctx, minLevel) {
+				return next.RoundTrip(req)
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("path", r.path(req.URL)),
+			}
+
+			if attempt, ok := AttemptFromContext(ctx); ok {
+				attrs = append(attrs, slog.Int("attempt", attempt.Number))
+			}
+
+			if opts.BodyLimit > 0 && req.GetBody != nil {
+				if body, err := req.GetBody(); err == nil {
+					data, _ := io.ReadAll(body)
+					_ = body.Close()
+
+					if len(data) > 0 {
+						attrs = append(attrs, slog.String("request_body", r.body(data, opts.BodyLimit, false)))
+					}
+				}
+			}
+
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+			if err != nil {
+				attrs = append(attrs, slog.String("error", r.text(err.Error())))
+				logger.LogAttrs(ctx, opts.ErrorLevel.Level(), "github request failed", attrs...)
+
+				return resp, err
+			}
+
+			attrs = append(attrs,
+				slog.Int("status", resp.StatusCode),
+				slog.String("request_id", resp.Header.Get(requestIDHeader)),
+			)
+
+			if resp.Header.Get(rateRemainigHeader) != "" {
+				attrs = append(attrs, slog.Group("rate_limit",
+					slog.String("resource", resp.Header.Get(rateResourceHeader)),
+					slog.String("limit", resp.Header.Get(rateLimitHeader)),
+					slog.String("remaining", resp.Header.Get(rateRemainigHeader)),
+					slog.String("reset", resp.Header.Get(rateResetHeader)),
+				))
+			}
+
+			if opts.BodyLimit > 0 && resp.Body != nil {
+				n := opts.BodyLimit + redactMargin
+				if head := peekBodyHead(resp, n); len(head) > 0 {
+					attrs = append(attrs, slog.String("response_body", r.body(head, opts.BodyLimit, len(head) == n)))
+				}
+			}
+
+			level := opts.Level.Level()
+			if resp.StatusCode >= 400 {
+				level = opts.ErrorLevel.Level()
+			}
+
+			logger.LogAttrs(ctx, level, "github request", attrs...)
+
+			return resp, nil
+		})
+	}
+}
+
+// redactMargin is read beyond the logged part of a response body, so that
+// a credential crossing the limit is still recognized and redacted.
+const redactMargin = 256
+
+// peekBodyHead reads up to n bytes of the body of resp and puts them back in
+// front of the rest, which is left unread so that downloads keep
+// streaming. A read error is returned by the body once the bytes before
+// it were read.
+func peekBodyHead(resp *http.Response, n int) []byte {
+	head, err := io.ReadAll(io.LimitReader(resp.Body, int64(n)))
+
+	rest := resp.Body
+	if err != nil {
+		rest = io.NopCloser(&errReader{err: err})
+	}
+
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), rest), resp.Body}
+
+	if err != nil {
+		return nil
+	}
+
+	return head
+}
+
+// errReader returns err from every read.
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// redactor removes credentials from logged values.
+type redactor struct {
+	fields []string
+}
+
+// isSecret reports whether the value of the named field must be redacted.
+func (r *redactor) isSecret(name string) bool {
+	name = strings.ToLower(name)
+	if strings.HasSuffix(name, "_url") {
+		return false
+	}
+
+	for _, field := range r.fields {
+		if strings.Contains(name, strings.ToLower(field)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// text redacts credentials that match a known format.
+func (r *redactor) text(s string) string {
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, redacted)
+	}
+
+	return s
+}
+
+// path returns the path and query of u with secret query parameters redacted.
+func (r *redactor) path(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+
+	query := u.Query()
+	for name, values := range query {
+		if r.isSecret(name) {
+			for i := range values {
+				values[i] = redacted
+			}
+		}
+	}
+
+	rawQuery := strings.ReplaceAll(query.Encode(), url.QueryEscape(redacted), redacted)
+
+	return r.text(u.Path + "?" + rawQuery)
+}
+
+// body redacts a request or response body and truncates it to limit bytes.
+// Secret fields are redacted by name, by decoding the body if it is JSON
+// and by matching "name": "value" pairs otherwise, which covers partial
+// and malformed JSON. Credentials are also redacted by their format.
+// A partial body is only the beginning of a longer one.
+func (r *redactor) body(data []byte, limit int, partial bool) string {
+	var s string
+
+	var v any
+	if err := json.Unmarshal(data, &v); err == nil {
+		if out, err := json.Marshal(r.value(v)); err == nil {
+			s = string(out)
+		}
+	}
+
+	if s == "" {
+		s = r.jsonFields(string(data))
+	}
+
+	s = r.text(s)
+	switch {
+	case partial:
+		s = s[:min(limit, len(s))] + "... (truncated)"
+	case len(s) > limit:
+		s = fmt.Sprintf("%s... (%d bytes truncated)", s[:limit], len(s)-limit)
+	}
+
+	return s
+}
+
+// jsonFields redacts the string values of secret fields in text that is not
+// valid JSON.
+func (r *redactor) jsonFields(s string) string {
+	return jsonStringField.ReplaceAllStringFunc(s, func(field string) string {
+		m := jsonStringField.FindStringSubmatch(field)
+		if !r.isSecret(m[1]) {
+			return field
+		}
+
+		return `"` + m[1] + `"` + m[2] + `"` + redacted + `"`
+	})
+}
+
+func (r *redactor) value(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if _, ok := field.(string); ok && r.isSecret(key) {
+				v[key] = redacted
+				continue
+			}
+
+			v[key] = r.value(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = r.value(item)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,54 @@
+package github
+
+import "fmt"
+
+// Media types that select the representation of a response.
+// GitHub API docs: https://docs.github.com/en/rest/using-the-rest-api/getting-started-with-the-rest-api#media-types
+const (
+	// MediaTypeJSON is the default representation of every response
+	MediaTypeJSON = "application/vnd.github.v3+json"
+
+	// MediaTypeRaw returns Markdown bodies unrendered in the body field,
+	// or the raw bytes of a file from the contents API
+	MediaTypeRaw = "application/vnd.github.raw+json"
+
+	// MediaTypeText returns a plain text rendering of Markdown bodies
+	// in the body_text field
+	MediaTypeText = "application/vnd.github.text+json"
+
+	// MediaTypeHTML returns an HTML rendering of Markdown bodies in the
+	// body_html field
+	MediaTypeHTML = "application/vnd.github.html+json"
+
+	// MediaTypeFull returns the raw, text and HTML renderings of Markdown
+	// bodies in the body, body_text and body_html fields
+	MediaTypeFull = "application/vnd.github.full+json"
+
+	mediaTypeDiff  = "application/vnd.github.diff"
+	mediaTypePatch = "application/vnd.github.patch"
+)
+
+// RawType selects the format of a raw representation of a commit
+// range, such as the changes of a pull request.
+type RawType uint8
+
+const (
+	// RawDiff is the unified diff format
+	RawDiff RawType = 1 + iota
+
+	// RawPatch is the format of git format-patch, which includes the
+	// message and author of every commit
+	RawPatch
+)
+
+// mediaType returns the media type that requests t.
+func (t RawType) mediaType() (string, error) {
+	switch t {
+	case RawDiff:
+		return mediaTypeDiff, nil
+	case RawPatch:
+		return mediaTypePatch, nil
+	default:
+		return "", fmt.Errorf("unsupported raw type %d", t)
+	}
+}
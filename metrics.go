@@ -0,0 +1,214 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultMetricsBuckets are the upper bounds in seconds of the request
+// duration histogram buckets used by NewMetrics.
+var DefaultMetricsBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Metrics is an Instrumentation that aggregates request metrics and
+// serves them in the Prometheus text exposition format. It records
+//
+//   - github_client_requests_total, the number of requests by method,
+//     route and status code
+//   - github_client_request_duration_seconds, a histogram of the request
+//     duration including retries by method and route
+//   - github_client_retries_total, the number of attempts with a retryable
+//     response by method, route and retry reason
+//   - github_client_rate_limit_remaining and github_client_rate_limit_limit,
+//     the last reported quota by rate limit resource
+//
+// A Metrics is safe for concurrent use and may be shared between clients.
+type Metrics struct {
+	mu        sync.Mutex
+	buckets   []float64
+	requests  map[metricKey]float64
+	durations map[metricKey]*histogram
+	retries   map[metricKey]float64
+	remaining map[metricKey]float64
+	limits    map[metricKey]float64
+}
+
+// metricKey holds the label values of a series, in the order of the
+// label names passed to writeFamily.
+type metricKey [3]string
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewMetrics creates a Metrics that records request durations in the
+// given histogram buckets, or in DefaultMetricsBuckets if none are given.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultMetricsBuckets
+	}
+
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &Metrics{
+		buckets:   buckets,
+		requests:  make(map[metricKey]float64),
+		durations: make(map[metricKey]*histogram),
+		retries:   make(map[metricKey]float64),
+		remaining: make(map[metricKey]float64),
+		limits:    make(map[metricKey]float64),
+	}
+}
+
+// RequestStart implements Instrumentation.
+func (m *Metrics) RequestStart(ctx context.Context, _ RequestInfo) context.Context {
+	return ctx
+}
+
+// RequestAttempt implements Instrumentation.
+func (m *Metrics) RequestAttempt(_ context.Context, attempt AttemptInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if attempt.RetryReason != "" {
+		m.retries[metricKey{attempt.Method, attempt.Route, string(attempt.RetryReason)}]++
+	}
+
+	if attempt.RateLimit != nil {
+		key := metricKey{attempt.Resource}
+		m.remaining[key] = float64(attempt.RateLimit.Remaining)
+		m.limits[key] = float64(attempt.RateLimit.Limit)
+	}
+}
+
+// RequestEnd implements Instrumentation.
+func (m *Metrics) RequestEnd(_ context.Context, result RequestResult) {
+	status := "error"
+	if result.StatusCode != 0 {
+		status = strconv.Itoa(result.StatusCode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[metricKey{result.Method, result.Route, status}]++
+
+	key := metricKey{result.Method, result.Route}
+
+	h, ok := m.durations[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[key] = h
+	}
+
+	seconds := result.Duration.Seconds()
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+
+	h.count++
+	h.sum += seconds
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	_, _ = m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format to w.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	writeFamily(&b, "github_client_requests_total", "Number of API requests.", "counter",
+		[]string{"method", "route", "status"}, m.requests)
+
+	writeHistogram(&b, "github_client_request_duration_seconds", "Duration of API requests including retries.",
+		[]string{"method", "route"}, m.buckets, m.durations)
+
+	writeFamily(&b, "github_client_retries_total", "Number of request attempts with a retryable response.", "counter",
+		[]string{"method", "route", "reason"}, m.retries)
+
+	writeFamily(&b, "github_client_rate_limit_remaining", "Remaining rate limit quota.", "gauge",
+		[]string{"resource"}, m.remaining)
+
+	writeFamily(&b, "github_client_rate_limit_limit", "Rate limit quota.", "gauge",
+		[]string{"resource"}, m.limits)
+
+	n, err := io.WriteString(w, b.String())
+
+	return int64(n), err
+}
+
+func writeFamily(b *strings.Builder, name, help, typ string, labels []string, series map[metricKey]float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+
+	for _, key := range sortedKeys(series) {
+		fmt.Fprintf(b, "%s{%s} %s\n", name, formatLabels(labels, key), formatValue(series[key]))
+	}
+}
+
+func writeHistogram(b *strings.Builder, name, help string, labels []string, buckets []float64, series map[metricKey]*histogram) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+
+	for _, key := range sortedKeys(series) {
+		h := series[key]
+		base := formatLabels(labels, key)
+
+		for i, bound := range buckets {
+			fmt.Fprintf(b, "%s_bucket{%s,le=\"%s\"} %d\n", name, base, formatValue(bound), h.counts[i])
+		}
+
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, base, h.count)
+		fmt.Fprintf(b, "%s_sum{%s} %s\n", name, base, formatValue(h.sum))
+		fmt.Fprintf(b, "%s_count{%s} %d\n", name, base, h.count)
+	}
+}
+
+func sortedKeys[V any](series map[metricKey]V) []metricKey {
+	keys := make([]metricKey, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		for k := range keys[i] {
+			if keys[i][k] != keys[j][k] {
+				return keys[i][k] < keys[j][k]
+			}
+		}
+
+		return false
+	})
+
+	return keys
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(names []string, values metricKey) string {
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=\"%s\"", name, labelEscaper.Replace(values[i]))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,112 @@
+package github
+
+import (
+	"context"
+	"net/http"
+)
+
+// Middleware wraps the transport that sends a single attempt of a request.
+// A middleware may inspect or modify the request, abort it by returning an
+// error, substitute a response without calling next, or inspect and modify
+// the response returned by next. Modifications of the request headers must
+// be done on a clone of the header, as the request is reused for retries.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to allow the use of ordinary functions
+// as an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Attempt describes an attempt of sending a request. It is carried by the
+// context of the request passed to middleware.
+type Attempt struct {
+	// Number is the number of the attempt, starting at 1
+	Number int
+
+	// Max is the maximum number of attempts the client makes
+	Max int
+
+	// Resource is the rate limit resource the request is expected to
+	// count against
+	Resource string
+
+	// RetryReason is the reason the previous attempt was retried,
+	// or empty for the first attempt
+	RetryReason RetryReason
+}
+
+type attemptKey struct{}
+
+// AttemptFromContext returns the attempt carried by ctx. It is available
+// in the context of every request passed to middleware.
+func AttemptFromContext(ctx context.Context) (Attempt, bool) {
+	attempt, ok := ctx.Value(attemptKey{}).(Attempt)
+
+	return attempt, ok
+}
+
+// buildTransport chains the middleware of the client around its HTTP
+// client. The first middleware is the outermost one, so it sees the
+// request first and the response last. The request and response hooks
+// run inside all middleware, and logging is innermost, so it records the
+// requests as they are sent.
+func (c *Client) buildTransport() http.RoundTripper {
+	var transport http.RoundTripper = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Context().Value(noRedirectKey{}) != nil {
+			return c.noRedirectClient.Do(req)
+		}
+
+		return c.client.Do(req)
+	})
+
+	if c.logger != nil {
+		transport = loggingMiddleware(c.logger, c.logOptions)(transport)
+	}
+
+	if c.responseHook != nil {
+		transport = responseHookMiddleware(c.responseHook)(transport)
+	}
+
+	if c.requestHook != nil {
+		transport = requestHookMiddleware(c.requestHook)(transport)
+	}
+
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		transport = c.middleware[i](transport)
+	}
+
+	return transport
+}
+
+// requestHookMiddleware calls hook before every attempt.
+func requestHookMiddleware(hook func(*http.Request)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			hook(req)
+
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// responseHookMiddleware calls hook with every response received.
+func responseHookMiddleware(hook func(*Response)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			httpresp, err := next.RoundTrip(req)
+			if err != nil {
+				return httpresp, err
+			}
+
+			resp, _ := newResponse(httpresp)
+			resp.RetryReason = checkRetry(resp)
+			hook(resp)
+
+			return httpresp, nil
+		})
+	}
+}
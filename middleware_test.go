@@ -0,0 +1,165 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			*calls = append(*calls, name+" request")
+
+			resp, err := next.RoundTrip(req)
+
+			*calls = append(*calls, name+" response")
+
+			return resp, err
+		})
+	}
+}
+
+func TestMiddleware_Order(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer ts.Close()
+
+	var calls []string
+
+	client, err := NewClient(
+		WithBaseURL(ts.URL),
+		WithMiddleware(recordingMiddleware("first", &calls), recordingMiddleware("second", &calls)),
+		WithMiddleware(recordingMiddleware("third", &calls)),
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"first request", "second request", "third request",
+		"third response", "second response", "first response",
+	}, calls)
+}
+
+func TestMiddleware_Attempts(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		assert.Equal(t, "yes", r.Header.Get("X-Traced"))
+
+		if requests == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer ts.Close()
+
+	var attempts []Attempt
+
+	client, err := NewClient(
+		WithBaseURL(ts.URL),
+		WithRateLimitRetry(true),
+		WithRetryWaitMin(time.Millisecond),
+		WithRetryWaitMax(time.Millisecond),
+		WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				attempt, ok := AttemptFromContext(req.Context())
+				require.True(t, ok)
+				attempts = append(attempts, attempt)
+
+				req = req.Clone(req.Context())
+				req.Header.Set("X-Traced", "yes")
+
+				return next.RoundTrip(req)
+			})
+		}),
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Attempt{
+		{Number: 1, Max: defaultRetryMax, Resource: ResourceCore},
+		{Number: 2, Max: defaultRetryMax, Resource: ResourceCore, RetryReason: RetryReasonServerError},
+	}, attempts)
+}
+
+func TestMiddleware_Abort(t *testing.T) {
+	errBlocked := errors.New("blocked by policy")
+
+	client, err := NewClient(
+		WithBaseURL("http://127.0.0.1:1"),
+		WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				return nil, errBlocked
+			})
+		}),
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.Users.Get(context.Background(), "octocat")
+	require.ErrorIs(t, err, errBlocked)
+}
+
+func TestMiddleware_SubstituteResponse(t *testing.T) {
+	client, err := NewClient(
+		WithBaseURL("http://127.0.0.1:1"),
+		WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"login": "stub"}`)),
+					Request:    req,
+				}, nil
+			})
+		}),
+	)
+	require.NoError(t, err)
+
+	user, _, err := client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+	assert.Equal(t, "stub", user.Login)
+}
+
+func TestHooks_ReplacePreviousHook(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer ts.Close()
+
+	var calls []string
+
+	client, err := NewClient(
+		WithBaseURL(ts.URL),
+		WithRequestHook(func(*http.Request) { calls = append(calls, "request 1") }),
+		WithRequestHook(func(*http.Request) { calls = append(calls, "request 2") }),
+		WithResponseHook(func(r *Response) { calls = append(calls, "response "+r.Status) }),
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"request 2", "response 200 OK"}, calls, "a hook replaces the previous one")
+}
+
+func TestWithMiddleware_Nil(t *testing.T) {
+	_, err := NewClient(WithMiddleware(nil))
+	require.Error(t, err)
+}
@@ -0,0 +1,343 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NodeID is a decoded global node ID, which identifies an object in the
+// GraphQL API and is reported as node_id by the REST API.
+// GitHub API docs: https://docs.github.com/en/graphql/guides/using-global-node-ids
+type NodeID struct {
+	// Type is the GraphQL type name of the object, such as Issue
+	Type string
+
+	// DatabaseID is the REST id of the object
+	DatabaseID int64
+
+	// Legacy reports whether the ID uses the legacy base64 format
+	// rather than the newer prefixed format
+	Legacy bool
+}
+
+// nodeIDPrefixes maps the prefixes of new-format node IDs to type names.
+var nodeIDPrefixes = map[string]string{
+	"BOT":  "Bot",
+	"CR":   "CheckRun",
+	"CS":   "CheckSuite",
+	"D":    "Discussion",
+	"DC":   "DiscussionComment",
+	"I":    "Issue",
+	"IC":   "IssueComment",
+	"LA":   "Label",
+	"M":    "Mannequin",
+	"MI":   "Milestone",
+	"O":    "Organization",
+	"PR":   "PullRequest",
+	"PRR":  "PullRequestReview",
+	"PRRC": "PullRequestReviewComment",
+	"PVT":  "ProjectV2",
+	"PVTI": "ProjectV2Item",
+	"R":    "Repository",
+	"RE":   "Release",
+	"RA":   "ReleaseAsset",
+	"T":    "Team",
+	"U":    "User",
+	"WF":   "Workflow",
+	"WFR":  "WorkflowRun",
+}
+
+var legacyNodeIDPattern = regexp.MustCompile(`^0(\d+):([A-Za-z0-9]+)$`)
+
+// ErrInvalidNodeID is returned by ParseNodeID for IDs it cannot decode.
+var ErrInvalidNodeID = errors.New("github: invalid node ID")
+
+// ParseNodeID decodes a global node ID of either format into its type
+// name and database ID. Legacy IDs such as MDQ6VXNlcjU4MzIzMQ== encode
+// "04:User583231" in base64, new IDs such as U_kgDOAAhEkg are made of
+// a type prefix and a base64url encoded MessagePack array that ends with
+// the database ID. IDs that do not end with a numeric ID, such as those
+// of commits, cannot be decoded.
+func ParseNodeID(id string) (NodeID, error) {
+	if prefix, payload, ok := strings.Cut(id, "_"); ok {
+		typ, known := nodeIDPrefixes[prefix]
+		if !known {
+			return NodeID{}, fmt.Errorf("%w: unknown prefix %q", ErrInvalidNodeID, prefix)
+		}
+
+		data, err := base64.RawURLEncoding.DecodeString(payload)
+		if err != nil {
+			return NodeID{}, fmt.Errorf("%w: %w", ErrInvalidNodeID, err)
+		}
+
+		values, err := decodeIntArray(data)
+		if err != nil || len(values) == 0 {
+			return NodeID{}, fmt.Errorf("%w: unsupported payload of %s", ErrInvalidNodeID, id)
+		}
+
+		return NodeID{Type: typ, DatabaseID: values[len(values)-1]}, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(id)
+	if err != nil {
+		return NodeID{}, fmt.Errorf("%w: %w", ErrInvalidNodeID, err)
+	}
+
+	m := legacyNodeIDPattern.FindStringSubmatch(string(data))
+	if m == nil {
+		return NodeID{}, fmt.Errorf("%w: %s", ErrInvalidNodeID, id)
+	}
+
+	// the type name may end with digits itself, so split it by its length
+	nameLen, err := strconv.Atoi(m[1])
+	if err != nil {
+		return NodeID{}, fmt.Errorf("%w: %s", ErrInvalidNodeID, id)
+	}
+
+	rest := m[2]
+	if nameLen <= 0 || nameLen >= len(rest) {
+		return NodeID{}, fmt.Errorf("%w: %s", ErrInvalidNodeID, id)
+	}
+
+	dbID, err := strconv.ParseInt(rest[nameLen:], 10, 64)
+	if err != nil {
+		return NodeID{}, fmt.Errorf("%w: %s", ErrInvalidNodeID, id)
+	}
+
+	return NodeID{Type: rest[:nameLen], DatabaseID: dbID, Legacy: true}, nil
+}
+
+// decodeIntArray decodes a MessagePack array of integers.
+func decodeIntArray(data []byte) ([]int64, error) {
+	r := bytes.NewReader(data)
+
+	head, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var n int
+
+	switch {
+	case head >= 0x90 && head <= 0x9f:
+		n = int(head & 0x0f)
+	case head == 0xdc:
+		var l uint16
+		if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+			return nil, err
+		}
+
+		n = int(l)
+	default:
+		return nil, fmt.Errorf("expected array, got 0x%02x", head)
+	}
+
+	values := make([]int64, n)
+
+	for i := range values {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case b <= 0x7f:
+			values[i] = int64(b)
+		case b >= 0xe0:
+			values[i] = int64(int8(b))
+		case b == 0xcc || b == 0xd0:
+			var v uint8
+			err = binary.Read(r, binary.BigEndian, &v)
+			values[i] = int64(v)
+			if b == 0xd0 {
+				values[i] = int64(int8(v))
+			}
+		case b == 0xcd || b == 0xd1:
+			var v uint16
+			err = binary.Read(r, binary.BigEndian, &v)
+			values[i] = int64(v)
+			if b == 0xd1 {
+				values[i] = int64(int16(v))
+			}
+		case b == 0xce || b == 0xd2:
+			var v uint32
+			err = binary.Read(r, binary.BigEndian, &v)
+			values[i] = int64(v)
+			if b == 0xd2 {
+				values[i] = int64(int32(v))
+			}
+		case b == 0xcf || b == 0xd3:
+			var v uint64
+			err = binary.Read(r, binary.BigEndian, &v)
+			values[i] = int64(v)
+		default:
+			return nil, fmt.Errorf("expected integer, got 0x%02x", b)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+const issueCommentFragment = `fragment issueCommentFields on IssueComment {
+  id databaseId body bodyHTML bodyText createdAt updatedAt
+  author { ...actorFields }
+  issue { number repository { nameWithOwner } }
+}`
+
+var nodeQuery = `query($id: ID!) {
+  node(id: $id) {
+    __typename
+    ... on Issue { ...issueFields }
+    ... on PullRequest { ...pullRequestFields }
+    ... on Repository { ...repositoryFields }
+    ... on User { ...userFields }
+    ... on Label { ...labelFields repository { nameWithOwner } }
+    ... on IssueComment { ...issueCommentFields }
+  }
+}
+` + strings.Join([]string{
+	issueFragment, pullRequestFragment, repositoryFragment, userFragment,
+	labelFragment, issueCommentFragment, actorFragment,
+}, "\n")
+
+type gqlIssueComment struct {
+	ID         string     `json:"id"`
+	DatabaseID int        `json:"databaseId"`
+	Body       string     `json:"body"`
+	BodyHTML   string     `json:"bodyHTML"`
+	BodyText   string     `json:"bodyText"`
+	CreatedAt  *Timestamp `json:"createdAt"`
+	UpdatedAt  *Timestamp `json:"updatedAt"`
+	Author     *gqlActor  `json:"author"`
+	Issue      struct {
+		Number     int `json:"number"`
+		Repository struct {
+			NameWithOwner string `json:"nameWithOwner"`
+		} `json:"repository"`
+	} `json:"issue"`
+}
+
+// Node looks up the object with the given global node ID and returns it
+// as the struct the REST services use for its type: *Issue, *PullRequest,
+// *Repository, *User, *Label or *IssueComment. Objects of other types
+// are reported as errors. A missing object is reported as a GraphQLError
+// that matches ErrNotFound.
+// GitHub API docs: https://docs.github.com/en/graphql/reference/queries#node
+func (c *Client) Node(ctx context.Context, id string, reqOpts ...RequestOption) (any, *Response, error) {
+	var data struct {
+		Node json.RawMessage `json:"node"`
+	}
+
+	resp, err := c.GraphQL(ctx, nodeQuery, map[string]any{"id": id}, &data, reqOpts...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	node, err := c.decodeNode(data.Node)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return node, resp, nil
+}
+
+// decodeNode maps the result of a node query to its REST struct.
+func (c *Client) decodeNode(data json.RawMessage) (any, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, fmt.Errorf("%w: node", ErrNotFound)
+	}
+
+	var typed struct {
+		Typename string `json:"__typename"`
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	switch typed.Typename {
+	case "Issue":
+		var v gqlIssue
+		if err = json.Unmarshal(data, &v); err == nil {
+			owner, repo := splitFullName(v.Repository.NameWithOwner)
+			return v.toIssue(c, IssueKey{Owner: owner, Repo: repo}), nil
+		}
+	case "PullRequest":
+		var v gqlPullRequest
+		if err = json.Unmarshal(data, &v); err == nil {
+			var owner, repo string
+			if v.Repository != nil {
+				owner, repo = splitFullName(v.Repository.NameWithOwner)
+			}
+
+			return v.toPullRequest(c, IssueKey{Owner: owner, Repo: repo}), nil
+		}
+	case "Repository":
+		var v gqlRepository
+		if err = json.Unmarshal(data, &v); err == nil {
+			return v.toRepository(c), nil
+		}
+	case "User":
+		var v gqlUser
+		if err = json.Unmarshal(data, &v); err == nil {
+			return v.toUser(c), nil
+		}
+	case "Label":
+		var v struct {
+			gqlLabel
+			Repository struct {
+				NameWithOwner string `json:"nameWithOwner"`
+			} `json:"repository"`
+		}
+		if err = json.Unmarshal(data, &v); err == nil {
+			owner, repo := splitFullName(v.Repository.NameWithOwner)
+			return toLabels(c, owner, repo, []gqlLabel{v.gqlLabel})[0], nil
+		}
+	case "IssueComment":
+		var v gqlIssueComment
+		if err = json.Unmarshal(data, &v); err == nil {
+			return v.toIssueComment(c), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported node type %s", typed.Typename)
+	}
+
+	return nil, fmt.Errorf("failed to decode %s node: %w", typed.Typename, err)
+}
+
+func (ic *gqlIssueComment) toIssueComment(c *Client) *IssueComment {
+	owner, repo := splitFullName(ic.Issue.Repository.NameWithOwner)
+
+	return &IssueComment{
+		ID:        ic.DatabaseID,
+		NodeID:    ic.ID,
+		URL:       c.apiURL("repos/%s/%s/issues/comments/%d", owner, repo, ic.DatabaseID),
+		Body:      ic.Body,
+		BodyHTML:  ic.BodyHTML,
+		BodyText:  ic.BodyText,
+		User:      ic.Author.toUser(c),
+		CreatedAt: ic.CreatedAt,
+		UpdatedAt: ic.UpdatedAt,
+		IssueURL:  c.apiURL("repos/%s/%s/issues/%d", owner, repo, ic.Issue.Number),
+	}
+}
+
+// splitFullName splits a full repository name into owner and name.
+func splitFullName(fullName string) (string, string) {
+	owner, name, _ := strings.Cut(fullName, "/")
+
+	return owner, name
+}
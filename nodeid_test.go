@@ -0,0 +1,173 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNodeID(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		expected    NodeID
+		expectError bool
+	}{
+		{
+			name:     "legacy user",
+			id:       "MDQ6VXNlcjU4MzIzMQ==",
+			expected: NodeID{Type: "User", DatabaseID: 583231, Legacy: true},
+		},
+		{
+			name:     "legacy repository",
+			id:       "MDEwOlJlcG9zaXRvcnkxMjk2MjY5",
+			expected: NodeID{Type: "Repository", DatabaseID: 1296269, Legacy: true},
+		},
+		{
+			name:     "legacy type name ending with a digit",
+			id:       "MDk6UHJvamVjdFYyMTIz",
+			expected: NodeID{Type: "ProjectV2", DatabaseID: 123, Legacy: true},
+		},
+		{
+			name:     "new user",
+			id:       "U_kgDOAAhEkg",
+			expected: NodeID{Type: "User", DatabaseID: 541842},
+		},
+		{
+			name:     "new issue scoped to a repository",
+			id:       "I_kwDOABPHjc4AAAu5",
+			expected: NodeID{Type: "Issue", DatabaseID: 3001},
+		},
+		{
+			name:     "new label with a 16 bit ID",
+			id:       "LA_kgDNC7k",
+			expected: NodeID{Type: "Label", DatabaseID: 3001},
+		},
+		{
+			name:        "unknown prefix",
+			id:          "ZZ_kgDNC7k",
+			expectError: true,
+		},
+		{
+			name:        "not base64",
+			id:          "not a node id",
+			expectError: true,
+		},
+		{
+			name:        "legacy without database ID",
+			id:          "MDQ6VXNlcg==",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNodeID(tt.id)
+			if tt.expectError {
+				require.ErrorIs(t, err, ErrInvalidNodeID)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestNodeID_REST(t *testing.T) {
+	var issue Issue
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 1, "node_id": "MDU6SXNzdWUx",
+		"labels": [{"id": 208045946, "node_id": "MDU6TGFiZWwyMDgwNDU5NDY="}]
+	}`), &issue))
+
+	assert.Equal(t, "MDU6SXNzdWUx", issue.NodeID)
+	assert.Equal(t, "MDU6TGFiZWwyMDgwNDU5NDY=", issue.Labels[0].NodeID)
+}
+
+func TestClient_Node(t *testing.T) {
+	nodes := map[string]string{
+		"I_1": `{"__typename": "Issue", "id": "I_1", "databaseId": 1, "number": 7, "state": "CLOSED",
+			"repository": {"nameWithOwner": "octocat/hello-world"},
+			"labels": {"nodes": [{"id": "LA_1", "name": "bug"}]}}`,
+		"PR_1": `{"__typename": "PullRequest", "id": "PR_1", "databaseId": 2, "number": 8,
+			"repository": {"nameWithOwner": "octocat/hello-world", "name": "hello-world"}}`,
+		"R_1":  `{"__typename": "Repository", "id": "R_1", "nameWithOwner": "octocat/hello-world"}`,
+		"U_1":  `{"__typename": "User", "id": "U_1", "login": "octocat"}`,
+		"LA_1": `{"__typename": "Label", "id": "LA_1", "name": "bug", "repository": {"nameWithOwner": "octocat/hello-world"}}`,
+		"IC_1": `{"__typename": "IssueComment", "id": "IC_1", "databaseId": 9, "body": "LGTM",
+			"issue": {"number": 7, "repository": {"nameWithOwner": "octocat/hello-world"}}}`,
+		"T_1": `{"__typename": "Team"}`,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body graphQLRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		id, _ := body.Variables["id"].(string)
+
+		node, ok := nodes[id]
+		if !ok {
+			_, _ = w.Write([]byte(`{"data": {"node": null}, "errors": [{"type": "NOT_FOUND", "path": ["node"],
+				"message": "Could not resolve to a node with the global id of 'missing'"}]}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data": {"node": ` + node + `}}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	node, _, err := client.Node(ctx, "I_1")
+	require.NoError(t, err)
+	require.IsType(t, &Issue{}, node)
+
+	issue := node.(*Issue)
+	assert.Equal(t, "I_1", issue.NodeID)
+	assert.Equal(t, "closed", issue.State)
+	assert.Equal(t, ts.URL+"/repos/octocat/hello-world/issues/7", issue.URL)
+	assert.Equal(t, "LA_1", issue.Labels[0].NodeID)
+
+	node, _, err = client.Node(ctx, "PR_1")
+	require.NoError(t, err)
+	require.IsType(t, &PullRequest{}, node)
+	assert.Equal(t, "PR_1", node.(*PullRequest).NodeID)
+	assert.Equal(t, ts.URL+"/repos/octocat/hello-world/pulls/8", node.(*PullRequest).URL)
+
+	node, _, err = client.Node(ctx, "R_1")
+	require.NoError(t, err)
+	require.IsType(t, &Repository{}, node)
+	assert.Equal(t, "R_1", node.(*Repository).NodeID)
+
+	node, _, err = client.Node(ctx, "U_1")
+	require.NoError(t, err)
+	require.IsType(t, &User{}, node)
+	assert.Equal(t, "octocat", node.(*User).Login)
+
+	node, _, err = client.Node(ctx, "LA_1")
+	require.NoError(t, err)
+	require.IsType(t, &Label{}, node)
+	assert.Equal(t, ts.URL+"/repos/octocat/hello-world/labels/bug", node.(*Label).URL)
+
+	node, _, err = client.Node(ctx, "IC_1")
+	require.NoError(t, err)
+	require.IsType(t, &IssueComment{}, node)
+	assert.Equal(t, "LGTM", node.(*IssueComment).Body)
+	assert.Equal(t, ts.URL+"/repos/octocat/hello-world/issues/comments/9", node.(*IssueComment).URL)
+
+	_, _, err = client.Node(ctx, "T_1")
+	require.ErrorContains(t, err, "unsupported node type Team")
+
+	_, _, err = client.Node(ctx, "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+}
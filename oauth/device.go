@@ -0,0 +1,142 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// Error codes returned while polling for a device flow token.
+const (
+	ErrCodeAuthorizationPending = "authorization_pending"
+	ErrCodeSlowDown             = "slow_down"
+	ErrCodeExpiredToken         = "expired_token"
+	ErrCodeAccessDenied         = "access_denied"
+)
+
+var (
+	// defaultPollInterval is used when GitHub does not specify an interval
+	defaultPollInterval = 5 * time.Second
+
+	// slowDownStep is added to the interval on every slow_down answer
+	slowDownStep = 5 * time.Second
+)
+
+// ErrDeviceCodeExpired is returned when the user did not authorize the
+// device before the device code expired.
+var ErrDeviceCodeExpired = errors.New("oauth: device code expired")
+
+// DeviceCode represents the answer to a device authorization request.
+// GitHub docs: https://docs.github.com/en/apps/oauth-apps/building-oauth-apps/authorizing-oauth-apps#device-flow
+type DeviceCode struct {
+	// DeviceCode identifies the device while polling for the token
+	DeviceCode string `json:"device_code"`
+
+	// UserCode is the code the user has to enter at VerificationURI
+	UserCode string `json:"user_code"`
+
+	// VerificationURI is the page where the user enters UserCode
+	VerificationURI string `json:"verification_uri"`
+
+	// ExpiresIn is the lifetime of the device code in seconds
+	ExpiresIn int `json:"expires_in"`
+
+	// Interval is the minimum number of seconds between polls
+	Interval int `json:"interval"`
+}
+
+// RequestDeviceCode starts the device flow. The returned user code must
+// be shown to the user together with the verification URI before calling
+// PollToken.
+func (c *Config) RequestDeviceCode(ctx context.Context) (*DeviceCode, error) {
+	form := url.Values{}
+	form.Set("client_id", c.ClientID)
+
+	if len(c.Scopes) != 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+
+	code := new(DeviceCode)
+	if err := c.post(ctx, "login/device/code", form, code); err != nil {
+		return nil, err
+	}
+
+	return code, nil
+}
+
+// PollToken polls GitHub until the user authorizes the device, honouring
+// the requested interval and backing off on slow_down answers. It returns
+// ErrDeviceCodeExpired if the code expires and the *Error reported by
+// GitHub if the user denies access.
+func (c *Config) PollToken(ctx context.Context, code *DeviceCode) (*Token, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	var deadline <-chan time.Time
+	if code.ExpiresIn > 0 {
+		timer := time.NewTimer(time.Duration(code.ExpiresIn) * time.Second)
+		defer timer.Stop()
+
+		deadline = timer.C
+	}
+
+	form := url.Values{}
+	form.Set("client_id", c.ClientID)
+	form.Set("device_code", code.DeviceCode)
+	form.Set("grant_type", deviceGrantType)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return nil, ErrDeviceCodeExpired
+		case <-time.After(interval):
+		}
+
+		token := new(Token)
+
+		err := c.post(ctx, "login/oauth/access_token", form, token)
+		if err == nil {
+			return token, nil
+		}
+
+		var oauthErr *Error
+		if !errors.As(err, &oauthErr) {
+			return nil, err
+		}
+
+		switch oauthErr.Code {
+		case ErrCodeAuthorizationPending:
+			continue
+		case ErrCodeSlowDown:
+			interval += slowDownStep
+		case ErrCodeExpiredToken:
+			return nil, ErrDeviceCodeExpired
+		default:
+			return nil, oauthErr
+		}
+	}
+}
+
+// DeviceFlow runs the whole device flow. It requests a device code, hands
+// it to prompt so the user can be told where to enter it, and waits for
+// the token.
+func (c *Config) DeviceFlow(ctx context.Context, prompt func(*DeviceCode) error) (*Token, error) {
+	code, err := c.RequestDeviceCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := prompt(code); err != nil {
+		return nil, err
+	}
+
+	return c.PollToken(ctx, code)
+}
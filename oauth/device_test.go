@@ -0,0 +1,136 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/haadi-coder/github"
+)
+
+func TestConfig_DeviceFlow(t *testing.T) {
+	defaultPollInterval = time.Millisecond
+	slowDownStep = time.Millisecond
+
+	var polls atomic.Int32
+	answers := []string{
+		`{"error": "authorization_pending"}`,
+		`{"error": "slow_down", "interval": 10}`,
+		`{"access_token": "gho_device", "token_type": "bearer", "scope": "repo"}`,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+		assert.Equal(t, "client-123", r.PostForm.Get("client_id"))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/login/device/code":
+			assert.Equal(t, "repo read:org", r.PostForm.Get("scope"))
+			_, _ = w.Write([]byte(`{
+				"device_code": "dev-code",
+				"user_code": "WDJB-MJHT",
+				"verification_uri": "https://github.com/login/device",
+				"expires_in": 900,
+				"interval": 0
+			}`))
+		case "/login/oauth/access_token":
+			assert.Equal(t, "dev-code", r.PostForm.Get("device_code"))
+			assert.Equal(t, deviceGrantType, r.PostForm.Get("grant_type"))
+
+			n := polls.Add(1)
+			_, _ = w.Write([]byte(answers[n-1]))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	cfg := &Config{
+		ClientID: "client-123",
+		Scopes:   []string{"repo", "read:org"},
+		BaseURL:  ts.URL,
+	}
+
+	var prompted *DeviceCode
+	token, err := cfg.DeviceFlow(context.Background(), func(code *DeviceCode) error {
+		prompted = code
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, prompted)
+	assert.Equal(t, "WDJB-MJHT", prompted.UserCode)
+	assert.Equal(t, "gho_device", token.AccessToken)
+	assert.Equal(t, int32(3), polls.Load())
+
+	client, err := github.NewClient(github.WithTokenSource(token))
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+}
+
+func TestConfig_PollToken_Errors(t *testing.T) {
+	defaultPollInterval = time.Millisecond
+
+	tests := []struct {
+		name     string
+		answer   string
+		expected func(t *testing.T, err error)
+	}{
+		{
+			name:   "expired token",
+			answer: `{"error": "expired_token"}`,
+			expected: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrDeviceCodeExpired)
+			},
+		},
+		{
+			name:   "access denied",
+			answer: `{"error": "access_denied", "error_description": "The user has denied your application access."}`,
+			expected: func(t *testing.T, err error) {
+				var oauthErr *Error
+				require.ErrorAs(t, err, &oauthErr)
+				assert.Equal(t, ErrCodeAccessDenied, oauthErr.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.answer))
+			}))
+			defer ts.Close()
+
+			cfg := &Config{ClientID: "client-123", BaseURL: ts.URL}
+
+			_, err := cfg.PollToken(context.Background(), &DeviceCode{DeviceCode: "dev-code"})
+			require.Error(t, err)
+
+			tt.expected(t, err)
+		})
+	}
+}
+
+func TestConfig_PollToken_ContextCancelled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error": "authorization_pending"}`))
+	}))
+	defer ts.Close()
+
+	cfg := &Config{ClientID: "client-123", BaseURL: ts.URL}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := cfg.PollToken(ctx, &DeviceCode{DeviceCode: "dev-code", Interval: 1})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
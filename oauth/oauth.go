@@ -0,0 +1,148 @@
+// Package oauth implements the OAuth flows used to obtain GitHub user
+// access tokens: the device authorization flow for command line tools and
+// the web application flow with PKCE for browser based logins.
+//
+// The resulting Token implements github.TokenSource, so it can be passed
+// to github.NewClient with github.WithTokenSource.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultBaseURL = "https://github.com/"
+
+// Config describes a GitHub OAuth app or GitHub App used for user login.
+type Config struct {
+	// ClientID is the client ID of the app
+	ClientID string
+
+	// ClientSecret is the client secret of the app. It is required by the
+	// web application flow and unused by the device flow.
+	ClientSecret string
+
+	// RedirectURL is the callback URL of the web application flow
+	RedirectURL string
+
+	// Scopes lists the requested OAuth scopes
+	Scopes []string
+
+	// BaseURL is the URL of the GitHub web host, defaulting to
+	// https://github.com/. Set it for GitHub Enterprise Server or tests.
+	BaseURL string
+
+	// HTTPClient is used for all requests, defaulting to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// Token represents an OAuth access token returned by GitHub.
+type Token struct {
+	AccessToken           string `json:"access_token"`
+	TokenType             string `json:"token_type"`
+	Scope                 string `json:"scope"`
+	RefreshToken          string `json:"refresh_token,omitempty"`
+	ExpiresIn             int    `json:"expires_in,omitempty"`
+	RefreshTokenExpiresIn int    `json:"refresh_token_expires_in,omitempty"`
+}
+
+// Token returns the access token, which lets a Token be used as a
+// github.TokenSource.
+func (t *Token) Token(context.Context) (string, error) {
+	return t.AccessToken, nil
+}
+
+// Error represents an error returned by the GitHub OAuth endpoints.
+// GitHub docs: https://docs.github.com/en/apps/oauth-apps/maintaining-oauth-apps/troubleshooting-oauth-app-access-token-request-errors
+type Error struct {
+	// Code is the error code, such as "bad_verification_code"
+	Code string `json:"error"`
+
+	// Description is a human readable explanation of the error
+	Description string `json:"error_description"`
+
+	// URI links to the documentation of the error
+	URI string `json:"error_uri"`
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("oauth error: %s", e.Code)
+	}
+
+	return fmt.Sprintf("oauth error: %s - %s", e.Code, e.Description)
+}
+
+func (c *Config) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+
+	return http.DefaultClient
+}
+
+func (c *Config) endpoint(path string) (string, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = defaultBaseURL
+	}
+
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse base URL %s: %w", base, err)
+	}
+
+	return u.JoinPath(path).String(), nil
+}
+
+// post sends a form encoded request to path and decodes the JSON answer
+// into v. OAuth errors reported in the body are returned as *Error.
+func (c *Config) post(ctx context.Context, path string, form url.Values, v any) error {
+	endpoint, err := c.endpoint(path)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	oauthErr := new(Error)
+	if err := json.Unmarshal(body, oauthErr); err == nil && oauthErr.Code != "" {
+		return oauthErr
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// AuthCodeURL returns the URL of the page asking the user to authorize
+// the app. The state must be checked against the state parameter of the
+// callback to prevent request forgery. If verifier is not empty, its S256
+// challenge is sent so the code can only be exchanged with the verifier.
+func (c *Config) AuthCodeURL(state string, verifier string) string {
+	v := url.Values{}
+	v.Set("client_id", c.ClientID)
+	v.Set("state", state)
+
+	if c.RedirectURL != "" {
+		v.Set("redirect_uri", c.RedirectURL)
+	}
+
+	if len(c.Scopes) != 0 {
+		v.Set("scope", strings.Join(c.Scopes, " "))
+	}
+
+	if verifier != "" {
+		v.Set("code_challenge", S256Challenge(verifier))
+		v.Set("code_challenge_method", "S256")
+	}
+
+	endpoint, err := c.endpoint("login/oauth/authorize")
+	if err != nil {
+		return ""
+	}
+
+	return endpoint + "?" + v.Encode()
+}
+
+// Exchange trades the code received on the callback for an access token.
+// The verifier must be the one passed to AuthCodeURL, or empty if PKCE
+// was not used.
+func (c *Config) Exchange(ctx context.Context, code string, verifier string) (*Token, error) {
+	form := url.Values{}
+	form.Set("client_id", c.ClientID)
+	form.Set("client_secret", c.ClientSecret)
+	form.Set("code", code)
+
+	if c.RedirectURL != "" {
+		form.Set("redirect_uri", c.RedirectURL)
+	}
+
+	if verifier != "" {
+		form.Set("code_verifier", verifier)
+	}
+
+	token := new(Token)
+	if err := c.post(ctx, "login/oauth/access_token", form, token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// GenerateState returns a random value for the state parameter.
+func GenerateState() (string, error) {
+	return randomString(16)
+}
+
+// GenerateVerifier returns a random PKCE code verifier.
+func GenerateVerifier() (string, error) {
+	return randomString(32)
+}
+
+// S256Challenge derives the PKCE code challenge of a verifier.
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
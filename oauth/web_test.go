@@ -0,0 +1,106 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_AuthCodeURL(t *testing.T) {
+	cfg := &Config{
+		ClientID:    "client-123",
+		RedirectURL: "http://localhost:8080/callback",
+		Scopes:      []string{"repo", "user"},
+	}
+
+	raw := cfg.AuthCodeURL("state-xyz", "verifier-abc")
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	assert.Equal(t, "github.com", u.Host)
+	assert.Equal(t, "/login/oauth/authorize", u.Path)
+
+	q := u.Query()
+	assert.Equal(t, "client-123", q.Get("client_id"))
+	assert.Equal(t, "state-xyz", q.Get("state"))
+	assert.Equal(t, "http://localhost:8080/callback", q.Get("redirect_uri"))
+	assert.Equal(t, "repo user", q.Get("scope"))
+	assert.Equal(t, S256Challenge("verifier-abc"), q.Get("code_challenge"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+}
+
+func TestConfig_Exchange(t *testing.T) {
+	tests := []struct {
+		name        string
+		answer      string
+		expectError bool
+	}{
+		{
+			name:   "success",
+			answer: `{"access_token": "gho_web", "token_type": "bearer", "scope": "repo"}`,
+		},
+		{
+			name:        "bad verification code",
+			answer:      `{"error": "bad_verification_code", "error_description": "The code passed is incorrect or expired."}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/login/oauth/access_token", r.URL.Path)
+				require.NoError(t, r.ParseForm())
+
+				assert.Equal(t, "client-123", r.PostForm.Get("client_id"))
+				assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+				assert.Equal(t, "the-code", r.PostForm.Get("code"))
+				assert.Equal(t, "verifier-abc", r.PostForm.Get("code_verifier"))
+
+				_, _ = w.Write([]byte(tt.answer))
+			}))
+			defer ts.Close()
+
+			cfg := &Config{ClientID: "client-123", ClientSecret: "secret", BaseURL: ts.URL}
+
+			token, err := cfg.Exchange(context.Background(), "the-code", "verifier-abc")
+			if tt.expectError {
+				var oauthErr *Error
+				require.ErrorAs(t, err, &oauthErr)
+				assert.Equal(t, "bad_verification_code", oauthErr.Code)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "gho_web", token.AccessToken)
+
+			access, err := token.Token(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, "gho_web", access)
+		})
+	}
+}
+
+func TestGenerateVerifier(t *testing.T) {
+	a, err := GenerateVerifier()
+	require.NoError(t, err)
+
+	b, err := GenerateVerifier()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	assert.GreaterOrEqual(t, len(a), 43, "PKCE verifiers must be at least 43 characters")
+}
+
+func TestS256Challenge(t *testing.T) {
+	assert.Equal(t, "K5x6-41mFxTvpObzIPEdq05UvvzIql6sRCB8gV2ba04", S256Challenge("verifier-abc"))
+}
@@ -2,8 +2,9 @@ package github
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
-	"net/url"
+	"sync"
 	"time"
 )
 
@@ -20,6 +21,133 @@ func WithToken(token string) option {
 	}
 }
 
+// WithTokenSource configures the client to ask the given source for a
+// token before every request. When the API answers 401 Bad credentials,
+// the source is invalidated if it implements TokenInvalidator and the
+// request is retried once with a fresh token.
+func WithTokenSource(src TokenSource) option {
+	return func(c *Client) error {
+		if src == nil {
+			return fmt.Errorf("token source must not be nil")
+		}
+
+		c.tokenSource = src
+
+		return nil
+	}
+}
+
+// WithTokenPool configures the client to spread requests across the
+// tokens of the pool, always using the token with the most remaining
+// quota for the request's rate limit resource. When a token runs out,
+// rate limited requests are retried right away with another token if
+// WithRateLimitRetry is enabled.
+func WithTokenPool(pool *TokenPool) option {
+	return func(c *Client) error {
+		if pool == nil || pool.Len() == 0 {
+			return fmt.Errorf("token pool must contain at least one token")
+		}
+
+		c.tokenPool = pool
+
+		return nil
+	}
+}
+
+// WithPacer configures the client to pace its requests with the given
+// pacer, which learns the remaining quota from every response and delays
+// requests so the quota lasts until the limit resets. A pacer can be
+// shared between clients that use the same credentials.
+func WithPacer(pacer *Pacer) option {
+	return func(c *Client) error {
+		c.pacer = pacer
+
+		return nil
+	}
+}
+
+// WithScheduler configures the client to admit its requests through the
+// given scheduler. The priority of a request is taken from its context,
+// see ContextWithPriority. A scheduler can be shared between clients that
+// use the same credentials.
+func WithScheduler(s *Scheduler) option {
+	return func(c *Client) error {
+		c.scheduler = s
+
+		return nil
+	}
+}
+
+// WithRateLimitThreshold registers fn to be called when the remaining
+// quota of resource drops below threshold, a fraction of the limit
+// between 0 and 1. For example, a threshold of 0.1 for ResourceSearch
+// notifies when less than 10% of the search quota is left. The callback
+// is called once per drop, from the goroutine that sent the request.
+func WithRateLimitThreshold(resource string, threshold float64, fn RateLimitThresholdFunc) option {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("rate limit threshold callback must not be nil")
+		}
+
+		if threshold <= 0 || threshold > 1 {
+			return fmt.Errorf("rate limit threshold must be in (0, 1], got %v", threshold)
+		}
+
+		c.rateLimits.watch(&rateLimitWatcher{resource: resource, threshold: threshold, fn: fn})
+
+		return nil
+	}
+}
+
+// WithAppAuth configures the client to authenticate as a GitHub App.
+// Requests are signed with short-lived RS256 JWTs issued for appID from
+// the PEM encoded private key. Such a client can manage the app's
+// installations through the Apps service.
+func WithAppAuth(appID int64, privateKey []byte) option {
+	return func(c *Client) error {
+		src, err := newAppTokenSource(appID, privateKey)
+		if err != nil {
+			return fmt.Errorf("failed to configure app auth: %w", err)
+		}
+
+		c.app = src
+		c.tokenSource = src
+
+		return nil
+	}
+}
+
+// WithInstallationAuth configures the client to authenticate as an
+// installation of a GitHub App. Installation access tokens are created
+// on demand, cached and renewed shortly before they expire.
+func WithInstallationAuth(appID int64, installationID int64, privateKey []byte) option {
+	return func(c *Client) error {
+		src, err := newAppTokenSource(appID, privateKey)
+		if err != nil {
+			return fmt.Errorf("failed to configure installation auth: %w", err)
+		}
+
+		c.tokenSource = &installationTokenSource{
+			apps: sync.OnceValues(func() (*AppsService, error) {
+				app, err := NewClient(append(c.inheritedOptions(), func(app *Client) error {
+					app.app = src
+					app.tokenSource = src
+
+					return nil
+				})...)
+				if err != nil {
+					return nil, err
+				}
+
+				return app.Apps, nil
+			}),
+			installationID: installationID,
+		}
+
+		return nil
+	}
+}
+
 // WithHTTPClient configures the client to use the specified HTTP client
 // for making requests. This allows customization of the underlying HTTP
 // transport, timeouts, and other HTTP-related settings.
@@ -33,15 +161,33 @@ func WithHTTPClient(client *http.Client) option {
 
 // WithBaseURL configures the client to use the specified base URL for
 // all API requests. This is useful for testing against different API
-// endpoints or enterprise GitHub instances.
+// endpoints. A trailing slash is added to the path if it is missing, and
+// the GraphQL endpoint is moved below the base URL. Use WithEnterpriseURLs
+// or NewEnterpriseClient for GitHub Enterprise Server instances.
 func WithBaseURL(baseURL string) option {
 	return func(c *Client) error {
-		parsed, err := url.Parse(baseURL)
+		parsed, err := parseBaseURL(baseURL)
 		if err != nil {
 			return fmt.Errorf("failed to parse base URL %s: %w", baseURL, err)
 		}
 
 		c.baseURL = parsed
+		c.graphQLURL = graphQLEndpoint(parsed)
+
+		return nil
+	}
+}
+
+// WithUploadURL configures the client to use the specified base URL for
+// uploads. A trailing slash is added to the path if it is missing.
+func WithUploadURL(uploadURL string) option {
+	return func(c *Client) error {
+		parsed, err := parseBaseURL(uploadURL)
+		if err != nil {
+			return fmt.Errorf("failed to parse upload URL %s: %w", uploadURL, err)
+		}
+
+		c.uploadURL = parsed
 
 		return nil
 	}
@@ -113,9 +259,91 @@ func WithRetryWaitMax(wait time.Duration) option {
 	}
 }
 
+// WithRetryPolicy configures the policy that decides which failed
+// requests are retried. By default, rate limited requests are always
+// retried while server and network errors are retried only for
+// idempotent methods. The policy applies when WithRateLimitRetry is enabled.
+func WithRetryPolicy(policy RetryPolicy) option {
+	return func(c *Client) error {
+		if policy == nil {
+			return fmt.Errorf("retry policy must not be nil")
+		}
+
+		c.retryPolicy = policy
+
+		return nil
+	}
+}
+
+// WithMiddleware appends middleware to the chain that sends every attempt
+// of a request. Middleware run in the order they are added: the first one
+// sees the request first and the response last. The attempt being sent is
+// available from the request context through AttemptFromContext.
+func WithMiddleware(middleware ...Middleware) option {
+	return func(c *Client) error {
+		for _, m := range middleware {
+			if m == nil {
+				return fmt.Errorf("middleware must not be nil")
+			}
+		}
+
+		c.middleware = append(c.middleware, middleware...)
+
+		return nil
+	}
+}
+
+// WithLogger configures the client to log every attempt of a request
+// with its method, path, status, duration, attempt number, request ID and
+// rate limit state. Tokens, JWTs and secret-like fields are redacted.
+// See WithLogOptions to change levels and to log bodies.
+func WithLogger(logger *slog.Logger) option {
+	return func(c *Client) error {
+		c.logger = logger
+
+		return nil
+	}
+}
+
+// WithLogOptions configures the levels and body logging of the logger
+// set with WithLogger. Levels left nil keep their defaults.
+func WithLogOptions(opts LogOptions) option {
+	return func(c *Client) error {
+		if opts.BodyLimit < 0 {
+			return fmt.Errorf("log body limit must not be negative")
+		}
+
+		if opts.Level == nil {
+			opts.Level = defaultLogOptions.Level
+		}
+
+		if opts.ErrorLevel == nil {
+			opts.ErrorLevel = defaultLogOptions.ErrorLevel
+		}
+
+		c.logOptions = opts
+
+		return nil
+	}
+}
+
+// WithInstrumentation configures the client to report the start and end
+// of every request and each of its attempts to inst. See Metrics for a
+// built-in implementation that exports Prometheus metrics.
+func WithInstrumentation(inst Instrumentation) option {
+	return func(c *Client) error {
+		c.instrumentation = inst
+
+		return nil
+	}
+}
+
 // WithRequestHook configures a hook function that will be called before
 // each HTTP request is sent. This allows for request inspection,
 // logging, or modification before the request is executed.
+//
+// Deprecated: Use WithMiddleware, which can also abort requests and
+// see the attempt number.
 func WithRequestHook(hook func(*http.Request)) option {
 	return func(c *Client) error {
 		c.requestHook = hook
@@ -127,10 +355,26 @@ func WithRequestHook(hook func(*http.Request)) option {
 // WithResponseHook configures a hook function that will be called after
 // each HTTP response is received. This allows for response inspection,
 // logging, or custom processing of API responses.
+//
+// Deprecated: Use WithMiddleware, which can also substitute responses
+// and see the attempt number.
 func WithResponseHook(hook func(*Response)) option {
 	return func(c *Client) error {
 		c.responseHook = hook
-		
+
+		return nil
+	}
+}
+
+// WithCache configures the client to cache GET responses in the given
+// store. Cached responses are revalidated with If-None-Match and
+// If-Modified-Since, and the stored body is served when the API answers
+// 304 Not Modified, which does not count against the rate limit.
+// Requests authenticated from a TokenPool are not cached.
+func WithCache(store CacheStore) option {
+	return func(c *Client) error {
+		c.cache = store
+
 		return nil
 	}
 }
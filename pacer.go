@@ -0,0 +1,103 @@
+package github
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Pacer spreads the remaining rate limit quota evenly over the time left
+// until the limit resets, so that concurrent callers slow down gradually
+// instead of all stalling once the limit is exhausted. Each rate limit
+// resource, such as core, search, graphql and code_search, has its own
+// budget. A Pacer tracks the quota of a single set of credentials and is
+// safe for concurrent use.
+type Pacer struct {
+	mu      sync.Mutex
+	burst   int
+	buckets map[string]*paceBucket
+}
+
+type paceBucket struct {
+	remaining int
+	reset     time.Time
+
+	// tat is the theoretical arrival time of the next request
+	tat time.Time
+}
+
+// NewPacer creates a pacer that lets up to burst requests through
+// back to back before spacing them out. A burst below one is treated
+// as one, which spaces out every request.
+func NewPacer(burst int) *Pacer {
+	return &Pacer{
+		burst:   max(burst, 1),
+		buckets: make(map[string]*paceBucket),
+	}
+}
+
+// Wait blocks until a request against resource may be sent without
+// running ahead of the even spread of the remaining quota, or until the
+// context is done. Resources without known rate limit state are not paced.
+func (p *Pacer) Wait(ctx context.Context, resource string) error {
+	delay := p.reserve(resource, time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve books a slot for the next request and returns how long the
+// caller has to wait for it.
+func (p *Pacer) reserve(resource string, now time.Time) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b, ok := p.buckets[resource]
+	if !ok || !now.Before(b.reset) {
+		return 0
+	}
+
+	if b.remaining <= 0 {
+		return b.reset.Sub(now)
+	}
+
+	tat := b.tat
+	if tat.Before(now) {
+		tat = now
+	}
+
+	interval := b.reset.Sub(tat) / time.Duration(b.remaining)
+	b.remaining--
+	b.tat = tat.Add(interval)
+
+	return max(tat.Add(-time.Duration(p.burst-1)*interval).Sub(now), 0)
+}
+
+// Update records the rate limit state reported for resource.
+func (p *Pacer) Update(resource string, rl *RateLimit) {
+	if rl == nil || rl.Reset == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b, ok := p.buckets[resource]
+	if !ok {
+		b = &paceBucket{}
+		p.buckets[resource] = b
+	}
+
+	b.remaining = rl.Remaining
+	b.reset = time.Unix(rl.Reset, 0)
+}
@@ -0,0 +1,129 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacer_Reserve(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name     string
+		burst    int
+		rl       *RateLimit
+		requests int
+		expected []time.Duration
+	}{
+		{
+			name:     "unknown resource is not paced",
+			burst:    1,
+			requests: 2,
+			expected: []time.Duration{0, 0},
+		},
+		{
+			name:     "spreads quota evenly",
+			burst:    1,
+			rl:       &RateLimit{Remaining: 10, Reset: now.Add(10 * time.Second).Unix()},
+			requests: 3,
+			expected: []time.Duration{0, time.Second, 2 * time.Second},
+		},
+		{
+			name:     "burst passes through",
+			burst:    3,
+			rl:       &RateLimit{Remaining: 10, Reset: now.Add(10 * time.Second).Unix()},
+			requests: 4,
+			expected: []time.Duration{0, 0, 0, time.Second},
+		},
+		{
+			name:     "exhausted quota waits for reset",
+			burst:    5,
+			rl:       &RateLimit{Remaining: 0, Reset: now.Add(30 * time.Second).Unix()},
+			requests: 1,
+			expected: []time.Duration{30 * time.Second},
+		},
+		{
+			name:     "window already reset",
+			burst:    1,
+			rl:       &RateLimit{Remaining: 0, Reset: now.Add(-time.Second).Unix()},
+			requests: 1,
+			expected: []time.Duration{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pacer := NewPacer(tt.burst)
+			pacer.Update(ResourceCore, tt.rl)
+
+			var delays []time.Duration
+			for range tt.requests {
+				delays = append(delays, pacer.reserve(ResourceCore, now))
+			}
+
+			assert.Equal(t, tt.expected, delays)
+		})
+	}
+}
+
+func TestPacer_SeparateBudgets(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	pacer := NewPacer(1)
+	pacer.Update(ResourceSearch, &RateLimit{Remaining: 0, Reset: now.Add(time.Minute).Unix()})
+	pacer.Update(ResourceCore, &RateLimit{Remaining: 5000, Reset: now.Add(time.Hour).Unix()})
+
+	assert.Equal(t, time.Minute, pacer.reserve(ResourceSearch, now))
+	assert.Equal(t, time.Duration(0), pacer.reserve(ResourceCore, now))
+	assert.Equal(t, time.Duration(0), pacer.reserve(ResourceGraphQL, now))
+}
+
+func TestPacer_WaitContextCancelled(t *testing.T) {
+	pacer := NewPacer(1)
+	pacer.Update(ResourceCore, &RateLimit{Remaining: 0, Reset: time.Now().Add(time.Hour).Unix()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := pacer.Wait(ctx, ResourceCore)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDo_Pacer(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(rateLimitHeader, "30")
+		w.Header().Set(rateRemainigHeader, "0")
+		w.Header().Set(rateResetHeader, reset)
+		w.Header().Set(rateResourceHeader, ResourceSearch)
+		_, _ = w.Write([]byte(`{"total_count": 0, "items": []}`))
+	}))
+	defer ts.Close()
+
+	pacer := NewPacer(1)
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithPacer(pacer))
+	require.NoError(t, err)
+
+	_, _, err = client.Search.Repositories(context.Background(), "go", nil)
+	require.NoError(t, err)
+
+	_, _, err = client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err, "core budget is unaffected by the search budget")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, _, err = client.Search.Repositories(ctx, "go", nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"iter"
+)
+
+// pageFetcher retrieves a single page of results for the given
+// pagination parameters.
+type pageFetcher[T any] func(ctx context.Context, lo *ListOptions) ([]*T, *Response, error)
+
+// paginate returns an iterator that walks every page of a list endpoint.
+// It starts from the page described by lo, follows the next page number
+// or cursor reported in the Link header of each response and stops when
+// there are no more pages, the context is cancelled, or lo.MaxItems items
+// have been yielded. Endpoints that paginate by some other parameter,
+// such as since, must advance it themselves inside fetch; the iterator
+// stops if the next page URL does not change between two pages.
+func paginate[T any](ctx context.Context, lo *ListOptions, fetch pageFetcher[T]) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		opts := ListOptions{}
+		if lo != nil {
+			opts = *lo
+		}
+
+		count := 0
+		prevNextURL := ""
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			items, resp, err := fetch(ctx, &opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, item := range items {
+				if opts.MaxItems > 0 && count >= opts.MaxItems {
+					return
+				}
+
+				if !yield(item, nil) {
+					return
+				}
+
+				count++
+			}
+
+			if opts.MaxItems > 0 && count >= opts.MaxItems {
+				return
+			}
+
+			if resp == nil || len(items) == 0 {
+				return
+			}
+
+			switch {
+			case resp.NextPage != 0:
+				// a next page that does not advance would loop forever
+				if resp.NextPage <= opts.Page {
+					return
+				}
+				opts.Page = resp.NextPage
+			case resp.NextPageToken != "":
+				opts.After = resp.NextPageToken
+			case resp.NextURL != "":
+				// fetch advances its own position, unless it is stuck
+				if resp.NextURL == prevNextURL {
+					return
+				}
+
+				prevNextURL = resp.NextURL
+			default:
+				return
+			}
+		}
+	}
+}
+
+// eachRange returns an iterator that obtains a new iterator from seq on
+// every range. Iterators whose fetch function keeps state, such as a copy
+// of the caller's options, use it so that ranging twice or concurrently
+// starts from scratch and shares nothing.
+func eachRange[T any](seq func() iter.Seq2[*T, error]) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		seq()(yield)
+	}
+}
@@ -0,0 +1,310 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPagedServer(t *testing.T, pages int, perPage int) *httptest.Server {
+	t.Helper()
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			_, _ = fmt.Sscanf(p, "%d", &page)
+		}
+
+		if page < pages {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, ts.URL, r.URL.Path, page+1))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		items := make([]string, 0, perPage)
+		for i := range perPage {
+			items = append(items, fmt.Sprintf(`{"id": %d, "number": %d}`, (page-1)*perPage+i+1, (page-1)*perPage+i+1))
+		}
+
+		_, _ = fmt.Fprintf(w, "[%s]", strings.Join(items, ","))
+	}))
+
+	return ts
+}
+
+func TestIssuesService_ListByRepoAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *IssueListOptions
+		expected int
+	}{
+		{
+			name:     "all pages",
+			opts:     nil,
+			expected: 9,
+		},
+		{
+			name:     "max items within first page",
+			opts:     &IssueListOptions{ListOptions: &ListOptions{MaxItems: 2}},
+			expected: 2,
+		},
+		{
+			name:     "max items across pages",
+			opts:     &IssueListOptions{ListOptions: &ListOptions{MaxItems: 5}},
+			expected: 5,
+		},
+		{
+			name:     "start from later page",
+			opts:     &IssueListOptions{ListOptions: &ListOptions{Page: 3}},
+			expected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newPagedServer(t, 3, 3)
+			defer ts.Close()
+
+			client, err := NewClient(WithBaseURL(ts.URL))
+			require.NoError(t, err)
+
+			var numbers []int
+			for issue, err := range client.Issues.ListByRepoAll(context.Background(), "octocat", "Hello-World", tt.opts) {
+				require.NoError(t, err)
+				numbers = append(numbers, issue.Number)
+			}
+
+			assert.Len(t, numbers, tt.expected)
+		})
+	}
+}
+
+func TestPaginate_DoesNotMutateOptions(t *testing.T) {
+	ts := newPagedServer(t, 2, 2)
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	opts := &PullRequestListOptions{ListOptions: &ListOptions{PerPage: 2}}
+
+	count := 0
+	for _, err := range client.PullRequests.ListAll(context.Background(), "octocat", "Hello-World", opts) {
+		require.NoError(t, err)
+		count++
+	}
+
+	assert.Equal(t, 4, count)
+	assert.Equal(t, 0, opts.Page)
+}
+
+func TestPaginate_RangeTwice(t *testing.T) {
+	ts := newPagedServer(t, 3, 2)
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	issues := client.Issues.ListByRepoAll(context.Background(), "octocat", "Hello-World", &IssueListOptions{
+		ListOptions: &ListOptions{PerPage: 2},
+	})
+
+	collect := func() []int {
+		var numbers []int
+		for issue, err := range issues {
+			if !assert.NoError(t, err) {
+				return nil
+			}
+
+			numbers = append(numbers, issue.Number)
+		}
+
+		return numbers
+	}
+
+	expected := []int{1, 2, 3, 4, 5, 6}
+	assert.Equal(t, expected, collect())
+	assert.Equal(t, expected, collect(), "every range starts from the first page")
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, expected, collect())
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestPaginate_BreakStopsFetching(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Link", `<http://example.com/users/octocat/repos?page=2>; rel="next"`)
+		_, _ = w.Write([]byte(`[{"id": 1}, {"id": 2}]`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	for repo, err := range client.Repositories.ListAll(context.Background(), "octocat", nil) {
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), repo.ID)
+
+		break
+	}
+
+	assert.Equal(t, 1, requests)
+}
+
+func TestPaginate_ContextCancelled(t *testing.T) {
+	ts := newPagedServer(t, 3, 1)
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var lastErr error
+	count := 0
+	for _, err := range client.Users.ListAuthenticatedUserFollowersAll(ctx, nil) {
+		if err != nil {
+			lastErr = err
+			break
+		}
+
+		count++
+		cancel()
+	}
+
+	assert.Equal(t, 1, count)
+	assert.True(t, errors.Is(lastErr, context.Canceled))
+}
+
+func TestPaginate_ErrorStops(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	count := 0
+	for _, err := range client.Search.RepositoriesAll(context.Background(), "language:go", nil) {
+		require.Error(t, err)
+		count++
+	}
+
+	assert.Equal(t, 1, count)
+}
+
+func TestPaginate_Cursor(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("after") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/user/followers?after=abc>; rel="next"`, ts.URL))
+			_, _ = w.Write([]byte(`[{"id": 1}, {"id": 2}]`))
+		case "abc":
+			_, _ = w.Write([]byte(`[{"id": 3}]`))
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("after"))
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	var ids []int64
+	for user, err := range client.Users.ListAuthenticatedUserFollowersAll(context.Background(), nil) {
+		require.NoError(t, err)
+		ids = append(ids, user.ID)
+	}
+
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+}
+
+func TestPaginate_StuckNextURL(t *testing.T) {
+	fetches := 0
+	fetch := func(ctx context.Context, lo *ListOptions) ([]*User, *Response, error) {
+		fetches++
+		return []*User{{ID: 1}}, &Response{NextURL: "https://api.github.com/users?since=1"}, nil
+	}
+
+	count := 0
+	for _, err := range paginate(context.Background(), nil, fetch) {
+		require.NoError(t, err)
+		count++
+	}
+
+	assert.Equal(t, 2, fetches, "a next URL that does not advance ends the iteration")
+	assert.Equal(t, 2, count)
+}
+
+func TestPaginate_StuckNextPage(t *testing.T) {
+	var pages []int
+	fetch := func(ctx context.Context, lo *ListOptions) ([]*User, *Response, error) {
+		pages = append(pages, lo.Page)
+		return []*User{{ID: 1}}, &Response{NextPage: 2}, nil
+	}
+
+	count := 0
+	for _, err := range paginate(context.Background(), nil, fetch) {
+		require.NoError(t, err)
+		count++
+	}
+
+	assert.Equal(t, []int{0, 2}, pages, "a next page that does not advance ends the iteration")
+	assert.Equal(t, 2, count)
+}
+
+func TestUsersService_ListAll_Since(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("since") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/users?since=2>; rel="next"`, ts.URL))
+			_, _ = w.Write([]byte(`[{"id": 1}, {"id": 2}]`))
+		case "2":
+			_, _ = w.Write([]byte(`[{"id": 5}]`))
+		default:
+			t.Errorf("unexpected since %q", r.URL.Query().Get("since"))
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	users := client.Users.ListAll(context.Background(), nil)
+
+	for range 2 {
+		var ids []int64
+		for user, err := range users {
+			require.NoError(t, err)
+			ids = append(ids, user.ID)
+		}
+
+		assert.Equal(t, []int64{1, 2, 5}, ids, "every range starts from the first page")
+	}
+}
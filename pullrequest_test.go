@@ -406,3 +406,78 @@ func TestPullRequestsService_List(t *testing.T) {
 		})
 	}
 }
+
+func TestPullRequestsService_GetRaw(t *testing.T) {
+	tests := []struct {
+		name           string
+		rawType        RawType
+		expectedAccept string
+		responseBody   string
+	}{
+		{
+			name:           "diff",
+			rawType:        RawDiff,
+			expectedAccept: "application/vnd.github.diff",
+			responseBody:   "diff --git a/README.md b/README.md\n",
+		},
+		{
+			name:           "patch",
+			rawType:        RawPatch,
+			expectedAccept: "application/vnd.github.patch",
+			responseBody:   "From 6dcb09b Mon Sep 17 00:00:00 2001\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/repos/octocat/Hello-World/pulls/1", r.URL.Path)
+				assert.Equal(t, tt.expectedAccept, r.Header.Get("Accept"))
+
+				_, _ = w.Write([]byte(tt.responseBody))
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(WithBaseURL(ts.URL))
+			require.NoError(t, err)
+
+			raw, _, err := client.PullRequests.GetRaw(context.Background(), "octocat", "Hello-World", 1, tt.rawType)
+			require.NoError(t, err)
+			assert.Equal(t, tt.responseBody, raw)
+		})
+	}
+}
+
+func TestPullRequestsService_GetRaw_InvalidType(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+
+	_, _, err = client.PullRequests.GetRaw(context.Background(), "octocat", "Hello-World", 1, RawType(0))
+	require.Error(t, err)
+}
+
+func TestPullRequestsService_Get_FullMediaType(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, MediaTypeFull, r.Header.Get("Accept"))
+
+		_, _ = w.Write([]byte(`{
+            "number": 1,
+            "body": "**bold**",
+            "body_html": "<p><strong>bold</strong></p>",
+            "body_text": "bold"
+        }`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	pr, _, err := client.PullRequests.Get(context.Background(), "octocat", "Hello-World", 1, WithAccept(MediaTypeFull))
+	require.NoError(t, err)
+
+	assert.Equal(t, "**bold**", pr.Body)
+	assert.Equal(t, "<p><strong>bold</strong></p>", pr.BodyHTML)
+	assert.Equal(t, "bold", pr.BodyText)
+}
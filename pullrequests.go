@@ -3,8 +3,10 @@ package github
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 // PullRequestsService provides access to pull request-related API methods.
@@ -16,8 +18,11 @@ type PullRequestsService struct {
 // GitHub API docs: https://docs.github.com/en/rest/pulls/pulls
 type PullRequest struct {
 	ID                 int         `json:"id"`
+	NodeID             string      `json:"node_id"`
 	Title              string      `json:"title"`
 	Body               string      `json:"body"`
+	BodyHTML           string      `json:"body_html"`
+	BodyText           string      `json:"body_text"`
 	URL                string      `json:"url"`
 	Number             int         `json:"number"`
 	State              string      `json:"state"`
@@ -49,17 +54,18 @@ func (s *PullRequestsService) Get(
 	ctx context.Context,
 	owner string, repo string,
 	pull int,
+	reqOpts ...RequestOption,
 ) (*PullRequest, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/pulls/%d", owner, repo, pull)
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	pr := new(PullRequest)
 
-	resp, err := s.client.Do(ctx, req, pr)
+	resp, err := s.client.Do(ctx, req, pr, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -67,6 +73,41 @@ func (s *PullRequestsService) Get(
 	return pr, resp, nil
 }
 
+// GetRaw fetches the changes of a pull request in the given raw format,
+// either as a unified diff or as a patch series.
+// GitHub API docs: https://docs.github.com/en/rest/pulls/pulls#get-a-pull-request
+func (s *PullRequestsService) GetRaw(
+	ctx context.Context,
+	owner string,
+	repo string,
+	pull int,
+	rawType RawType,
+	reqOpts ...RequestOption,
+) (string, *Response, error) {
+	path := fmt.Sprintf("repos/%s/%s/pulls/%d", owner, repo, pull)
+
+	mediaType, err := rawType.mediaType()
+	if err != nil {
+		return "", nil, err
+	}
+
+	reqOpts = append(reqOpts[:len(reqOpts):len(reqOpts)], WithAccept(mediaType))
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var buf strings.Builder
+
+	resp, err := s.client.Do(ctx, req, &buf, reqOpts...)
+	if err != nil {
+		return "", resp, err
+	}
+
+	return buf.String(), resp, nil
+}
+
 // PullRequestCreateRequest represents the request body for creating a pull request.
 // GitHub API docs: https://docs.github.com/en/rest/pulls/pulls#create-a-pull-request
 type PullRequestCreateRequest struct {
@@ -90,17 +131,18 @@ func (s *PullRequestsService) Create(
 	owner string,
 	repo string,
 	body *PullRequestCreateRequest,
+	reqOpts ...RequestOption,
 ) (*PullRequest, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/pulls", owner, repo)
 
-	req, err := s.client.NewRequest(http.MethodPost, path, body)
+	req, err := s.client.NewRequest(http.MethodPost, path, body, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	pr := new(PullRequest)
 
-	resp, err := s.client.Do(ctx, req, pr)
+	resp, err := s.client.Do(ctx, req, pr, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -128,17 +170,18 @@ func (s *PullRequestsService) Update(
 	repo string,
 	pull int,
 	body *PullRequestUpdateRequest,
+	reqOpts ...RequestOption,
 ) (*PullRequest, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/pulls/%d", owner, repo, pull)
 
-	req, err := s.client.NewRequest(http.MethodPatch, path, body)
+	req, err := s.client.NewRequest(http.MethodPatch, path, body, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	pr := new(PullRequest)
 
-	resp, err := s.client.Do(ctx, req, pr)
+	resp, err := s.client.Do(ctx, req, pr, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -174,17 +217,18 @@ func (s *PullRequestsService) Merge(
 	repo string,
 	pull int,
 	body *MergeRequest,
+	reqOpts ...RequestOption,
 ) (*Merge, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/pulls/%d/merge", owner, repo, pull)
 
-	req, err := s.client.NewRequest(http.MethodPut, path, body)
+	req, err := s.client.NewRequest(http.MethodPut, path, body, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	merge := new(Merge)
 
-	resp, err := s.client.Do(ctx, req, merge)
+	resp, err := s.client.Do(ctx, req, merge, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -208,7 +252,7 @@ type PullRequestListOptions struct {
 // This method allows you to list pull requests with various filtering options
 // such as state (open, closed, all), source branch, target branch, and sorting.
 // The results are returned in pages according to the pagination options.
-func (s *PullRequestsService) List(ctx context.Context, owner string, repo string, opts *PullRequestListOptions) ([]*PullRequest, *Response, error) {
+func (s *PullRequestsService) List(ctx context.Context, owner string, repo string, opts *PullRequestListOptions, reqOpts ...RequestOption) ([]*PullRequest, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/pulls", owner, repo)
 
 	if opts != nil {
@@ -243,17 +287,35 @@ func (s *PullRequestsService) List(ctx context.Context, owner string, repo strin
 		}
 	}
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	prs := new([]*PullRequest)
 
-	res, err := s.client.Do(ctx, req, prs)
+	res, err := s.client.Do(ctx, req, prs, reqOpts...)
 	if err != nil {
 		return nil, res, err
 	}
 
 	return *prs, res, nil
 }
+
+// ListAll returns an iterator over all pull requests in a repository.
+// It transparently follows pagination links, starting from the page
+// set in opts, and stops early once opts.MaxItems pull requests have
+// been yielded or the context is cancelled.
+func (s *PullRequestsService) ListAll(ctx context.Context, owner string, repo string, opts *PullRequestListOptions, reqOpts ...RequestOption) iter.Seq2[*PullRequest, error] {
+	return eachRange(func() iter.Seq2[*PullRequest, error] {
+		o := PullRequestListOptions{}
+		if opts != nil {
+			o = *opts
+		}
+
+		return paginate(ctx, o.ListOptions, func(ctx context.Context, lo *ListOptions) ([]*PullRequest, *Response, error) {
+			o.ListOptions = lo
+			return s.List(ctx, owner, repo, &o, reqOpts...)
+		})
+	})
+}
@@ -3,8 +3,27 @@ package github
 import (
 	"context"
 	"net/http"
+	"strings"
+	"sync"
 )
 
+// Rate limit resources as reported in the X-RateLimit-Resource header.
+const (
+	ResourceCore                      = "core"
+	ResourceSearch                    = "search"
+	ResourceCodeSearch                = "code_search"
+	ResourceGraphQL                   = "graphql"
+	ResourceIntegrationManifest       = "integration_manifest"
+	ResourceSourceImport              = "source_import"
+	ResourceCodeScanningUpload        = "code_scanning_upload"
+	ResourceCodeScanningAutofix       = "code_scanning_autofix"
+	ResourceActionsRunnerRegistration = "actions_runner_registration"
+	ResourceSCIM                      = "scim"
+	ResourceDependencySnapshots       = "dependency_snapshots"
+)
+
+const rateResourceHeader = "X-RateLimit-Resource"
+
 // RateLimitService provides access to rate limit API methods.
 type RateLimitService struct {
 	client *Client
@@ -23,43 +42,187 @@ type RateLimit struct {
 
 // RateLimitResponse represents the complete rate limit information returned by the GitHub API.
 type RateLimitResponse struct {
-	Resources *RateLimitResources
-	Rate      *RateLimit
+	Resources *RateLimitResources `json:"resources"`
+	Rate      *RateLimit          `json:"rate"`
 }
 
 // RateLimitResources represents rate limits for different API resources.
 // Each field corresponds to a different category of GitHub API endpoints
 // with their own separate rate limits.
 type RateLimitResources struct {
-	Core                      *RateLimit
-	Search                    *RateLimit
-	Graphql                   *RateLimit
-	IntegrationManifest       *RateLimit
-	SourceImport              *RateLimit
-	CodeScanningUpload        *RateLimit
-	ActionsRunnerRegistration *RateLimit
-	Scim                      *RateLimit
-	DependencySnapshots       *RateLimit
-	CodeSearch                *RateLimit
-	CodeScanningAutofix       *RateLimit
+	Core                      *RateLimit `json:"core"`
+	Search                    *RateLimit `json:"search"`
+	Graphql                   *RateLimit `json:"graphql"`
+	IntegrationManifest       *RateLimit `json:"integration_manifest"`
+	SourceImport              *RateLimit `json:"source_import"`
+	CodeScanningUpload        *RateLimit `json:"code_scanning_upload"`
+	ActionsRunnerRegistration *RateLimit `json:"actions_runner_registration"`
+	Scim                      *RateLimit `json:"scim"`
+	DependencySnapshots       *RateLimit `json:"dependency_snapshots"`
+	CodeSearch                *RateLimit `json:"code_search"`
+	CodeScanningAutofix       *RateLimit `json:"code_scanning_autofix"`
+}
+
+// byResource returns the rate limits keyed by resource name.
+func (r *RateLimitResources) byResource() map[string]*RateLimit {
+	return map[string]*RateLimit{
+		ResourceCore:                      r.Core,
+		ResourceSearch:                    r.Search,
+		ResourceGraphQL:                   r.Graphql,
+		ResourceIntegrationManifest:       r.IntegrationManifest,
+		ResourceSourceImport:              r.SourceImport,
+		ResourceCodeScanningUpload:        r.CodeScanningUpload,
+		ResourceActionsRunnerRegistration: r.ActionsRunnerRegistration,
+		ResourceSCIM:                      r.Scim,
+		ResourceDependencySnapshots:       r.DependencySnapshots,
+		ResourceCodeSearch:                r.CodeSearch,
+		ResourceCodeScanningAutofix:       r.CodeScanningAutofix,
+	}
 }
 
 // Get retrieves the current rate limit status for the authenticated user.
 // This method returns detailed information about rate limits for all
 // API resources, including how many requests have been made, how many
-// are remaining, and when the limits will reset.
-func (s *RateLimitService) Get(ctx context.Context) (*RateLimitResponse, error) {
+// are remaining, and when the limits will reset. The result is also
+// recorded in the snapshot returned by Client.RateLimits.
+func (s *RateLimitService) Get(ctx context.Context, reqOpts ...RequestOption) (*RateLimitResponse, error) {
 	path := "rate_limit"
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
 
 	rl := new(RateLimitResponse)
-	if _, err := s.client.Do(ctx, req, rl); err != nil {
+	if _, err := s.client.Do(ctx, req, rl, reqOpts...); err != nil {
 		return nil, err
 	}
 
+	if rl.Resources != nil {
+		for resource, limit := range rl.Resources.byResource() {
+			if limit != nil {
+				s.client.rateLimits.update(resource, *limit)
+			}
+		}
+	}
+
 	return rl, nil
 }
+
+// RateLimits returns the last known rate limit of every resource the
+// client has sent requests against, keyed by resource name. The snapshot
+// is built from the rate limit headers of every response and from the
+// results of RateLimitService.Get.
+func (c *Client) RateLimits() map[string]RateLimit {
+	return c.rateLimits.snapshot()
+}
+
+// RateLimitThresholdFunc is called when the remaining quota of a rate
+// limit resource drops below a threshold registered with
+// WithRateLimitThreshold.
+type RateLimitThresholdFunc func(resource string, rl RateLimit)
+
+// rateLimitTracker keeps the last known rate limit of every resource and
+// notifies threshold watchers.
+type rateLimitTracker struct {
+	mu       sync.Mutex
+	limits   map[string]RateLimit
+	watchers []*rateLimitWatcher
+}
+
+type rateLimitWatcher struct {
+	resource  string
+	threshold float64
+	fn        RateLimitThresholdFunc
+
+	// fired is set once fn has been called and reset when the quota
+	// rises above the threshold again, so fn is called once per drop
+	fired bool
+}
+
+func newRateLimitTracker() *rateLimitTracker {
+	return &rateLimitTracker{limits: make(map[string]RateLimit)}
+}
+
+func (t *rateLimitTracker) snapshot() map[string]RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	limits := make(map[string]RateLimit, len(t.limits))
+	for resource, rl := range t.limits {
+		limits[resource] = rl
+	}
+
+	return limits
+}
+
+func (t *rateLimitTracker) watch(w *rateLimitWatcher) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.watchers = append(t.watchers, w)
+}
+
+// update records rl for resource and calls the watchers whose threshold
+// was crossed. Responses of concurrent requests may arrive out of order,
+// so within the same rate limit window the lowest remaining quota wins.
+func (t *rateLimitTracker) update(resource string, rl RateLimit) {
+	var notify []RateLimitThresholdFunc
+
+	t.mu.Lock()
+
+	if prev, ok := t.limits[resource]; ok {
+		if rl.Reset < prev.Reset || (rl.Reset == prev.Reset && rl.Remaining > prev.Remaining) {
+			t.mu.Unlock()
+			return
+		}
+	}
+
+	t.limits[resource] = rl
+
+	for _, w := range t.watchers {
+		if w.resource != resource || rl.Limit <= 0 {
+			continue
+		}
+
+		below := float64(rl.Remaining) < w.threshold*float64(rl.Limit)
+		if below && !w.fired {
+			notify = append(notify, w.fn)
+		}
+
+		w.fired = below
+	}
+
+	t.mu.Unlock()
+
+	for _, fn := range notify {
+		fn(resource, rl)
+	}
+}
+
+// requestResource guesses the rate limit resource a request counts
+// against from its path.
+func (c *Client) requestResource(req *http.Request) string {
+	path := strings.TrimPrefix(c.apiPath(req), "/")
+
+	switch {
+	case strings.HasPrefix(path, "search/code"):
+		return ResourceCodeSearch
+	case strings.HasPrefix(path, "search/"):
+		return ResourceSearch
+	case strings.HasPrefix(path, "graphql"):
+		return ResourceGraphQL
+	default:
+		return ResourceCore
+	}
+}
+
+// responseResource returns the resource reported by the response,
+// falling back to the resource guessed from the request.
+func responseResource(resp *Response, guessed string) string {
+	if resp.Resource != "" {
+		return resp.Resource
+	}
+
+	return guessed
+}
@@ -2,8 +2,11 @@ package github
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -171,7 +174,306 @@ func TestCalcBackoff(t *testing.T) {
 			t.Parallel()
 
 			result := calcBackoff(tt.waitMin, tt.waitMax, tt.attempt, &Response{RateLimit: &RateLimit{Reset: tt.reset}})
-			assert.InDelta(t, tt.expected, result, 0.5)
+			assert.LessOrEqual(t, result, tt.expected)
+			assert.GreaterOrEqual(t, result, max(tt.expected/2, tt.waitMin))
 		})
 	}
 }
+
+func TestCalcBackoff_RateLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		rateLimit   *RateLimit
+		reason      RetryReason
+		expectedMin time.Duration
+		expectedMax time.Duration
+	}{
+		{
+			name:        "Retry-After seconds",
+			headers:     map[string]string{"Retry-After": "7"},
+			rateLimit:   &RateLimit{Remaining: 10},
+			reason:      RetryReasonSecondaryRateLimit,
+			expectedMin: 7 * time.Second,
+			expectedMax: 7 * time.Second,
+		},
+		{
+			name:        "Retry-After HTTP date",
+			headers:     map[string]string{"Retry-After": time.Now().Add(20 * time.Second).UTC().Format(http.TimeFormat)},
+			rateLimit:   &RateLimit{Remaining: 10},
+			reason:      RetryReasonSecondaryRateLimit,
+			expectedMin: 18 * time.Second,
+			expectedMax: 20 * time.Second,
+		},
+		{
+			name:        "primary limit waits for reset",
+			rateLimit:   &RateLimit{Remaining: 0, Reset: time.Now().Add(10 * time.Second).Unix()},
+			reason:      RetryReasonRateLimit,
+			expectedMin: 8 * time.Second,
+			expectedMax: 10 * time.Second,
+		},
+		{
+			name:        "server error ignores reset",
+			rateLimit:   &RateLimit{Remaining: 0, Reset: time.Now().Add(time.Hour).Unix()},
+			reason:      RetryReasonServerError,
+			expectedMin: 500 * time.Millisecond,
+			expectedMax: time.Second,
+		},
+		{
+			name:        "secondary limit without Retry-After waits a minute",
+			rateLimit:   &RateLimit{Remaining: 10},
+			reason:      RetryReasonSecondaryRateLimit,
+			expectedMin: 30 * time.Second,
+			expectedMax: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &Response{
+				Response:    &http.Response{Header: make(http.Header)},
+				RateLimit:   tt.rateLimit,
+				RetryReason: tt.reason,
+			}
+
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+
+			result := calcBackoff(500*time.Millisecond, 2*time.Minute, 0, resp)
+			assert.GreaterOrEqual(t, result, tt.expectedMin)
+			assert.LessOrEqual(t, result, tt.expectedMax)
+		})
+	}
+}
+
+func TestCheckRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		headers    map[string]string
+		body       string
+		expected   RetryReason
+	}{
+		{
+			name:       "primary rate limit",
+			statusCode: http.StatusForbidden,
+			headers:    map[string]string{rateRemainigHeader: "0"},
+			body:       `{"message": "API rate limit exceeded"}`,
+			expected:   RetryReasonRateLimit,
+		},
+		{
+			name:       "secondary rate limit by Retry-After",
+			statusCode: http.StatusTooManyRequests,
+			headers:    map[string]string{rateRemainigHeader: "4000", "Retry-After": "30"},
+			expected:   RetryReasonSecondaryRateLimit,
+		},
+		{
+			name:       "secondary rate limit by message",
+			statusCode: http.StatusForbidden,
+			headers:    map[string]string{rateRemainigHeader: "4000"},
+			body:       `{"message": "You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`,
+			expected:   RetryReasonSecondaryRateLimit,
+		},
+		{
+			name:       "plain forbidden",
+			statusCode: http.StatusForbidden,
+			headers:    map[string]string{rateRemainigHeader: "4000"},
+			body:       `{"message": "Resource not accessible by integration"}`,
+			expected:   "",
+		},
+		{
+			name:       "forbidden without rate limit headers",
+			statusCode: http.StatusForbidden,
+			body:       `{"message": "Must have admin rights to Repository."}`,
+			expected:   "",
+		},
+		{
+			name:       "server error",
+			statusCode: http.StatusBadGateway,
+			expected:   RetryReasonServerError,
+		},
+		{
+			name:       "success",
+			statusCode: http.StatusOK,
+			expected:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			httpresp := &http.Response{
+				StatusCode: tt.statusCode,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+
+			for k, v := range tt.headers {
+				httpresp.Header.Set(k, v)
+			}
+
+			resp, err := newResponse(httpresp)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, checkRetry(resp))
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(body), "body must remain readable")
+		})
+	}
+}
+
+func TestDo_SecondaryRateLimitRetry(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set(rateRemainigHeader, "4000")
+
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, `{"message": "You have exceeded a secondary rate limit."}`, http.StatusForbidden)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer ts.Close()
+
+	var reasons []RetryReason
+
+	client, err := NewClient(
+		WithBaseURL(ts.URL),
+		WithRateLimitRetry(true),
+		WithResponseHook(func(r *Response) {
+			reasons = append(reasons, r.RetryReason)
+		}),
+	)
+	require.NoError(t, err)
+
+	user, _, err := client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+
+	assert.Equal(t, "octocat", user.Login)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []RetryReason{RetryReasonSecondaryRateLimit, ""}, reasons)
+}
+
+func TestRateLimitService_Get_AllResources(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+            "resources": {
+                "core": {"limit": 5000, "remaining": 4999, "used": 1, "reset": 1717029203},
+                "graphql": {"limit": 5000, "remaining": 5000, "used": 0, "reset": 1717029203},
+                "integration_manifest": {"limit": 5000, "remaining": 5000, "used": 0, "reset": 1717029203},
+                "source_import": {"limit": 100, "remaining": 100, "used": 0, "reset": 1717029203},
+                "code_scanning_upload": {"limit": 1000, "remaining": 1000, "used": 0, "reset": 1717029203},
+                "actions_runner_registration": {"limit": 10000, "remaining": 10000, "used": 0, "reset": 1717029203},
+                "scim": {"limit": 15000, "remaining": 15000, "used": 0, "reset": 1717029203},
+                "dependency_snapshots": {"limit": 100, "remaining": 100, "used": 0, "reset": 1717029203},
+                "code_search": {"limit": 10, "remaining": 10, "used": 0, "reset": 1717029203},
+                "code_scanning_autofix": {"limit": 10, "remaining": 10, "used": 0, "reset": 1717029203}
+            }
+        }`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	result, err := client.RateLimit.Get(context.Background())
+	require.NoError(t, err)
+
+	for resource, rl := range result.Resources.byResource() {
+		if resource == ResourceSearch {
+			assert.Nil(t, rl)
+			continue
+		}
+
+		require.NotNil(t, rl, resource)
+		assert.Positive(t, rl.Limit, resource)
+	}
+
+	limits := client.RateLimits()
+	assert.Len(t, limits, 10)
+	assert.Equal(t, RateLimit{Limit: 10, Remaining: 10, Reset: 1717029203}, limits[ResourceCodeSearch])
+}
+
+func TestClient_RateLimits(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resource, limit := ResourceCore, "5000"
+		if strings.HasPrefix(r.URL.Path, "/search/") {
+			resource, limit = ResourceSearch, "30"
+		}
+
+		w.Header().Set(rateLimitHeader, limit)
+		w.Header().Set(rateRemainigHeader, "20")
+		w.Header().Set(rateUsedHeader, "10")
+		w.Header().Set(rateResetHeader, strconv.FormatInt(reset, 10))
+		w.Header().Set(rateResourceHeader, resource)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	assert.Empty(t, client.RateLimits())
+
+	_, _, err = client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+
+	_, resp, err := client.Search.Users(context.Background(), "octo", nil)
+	require.NoError(t, err)
+	assert.Equal(t, ResourceSearch, resp.Resource)
+
+	assert.Equal(t, map[string]RateLimit{
+		ResourceCore:   {Limit: 5000, Remaining: 20, Used: 10, Reset: reset},
+		ResourceSearch: {Limit: 30, Remaining: 20, Used: 10, Reset: reset},
+	}, client.RateLimits())
+}
+
+func TestRateLimitTracker_Threshold(t *testing.T) {
+	var calls []RateLimit
+
+	client, err := NewClient(WithRateLimitThreshold(ResourceSearch, 0.1, func(resource string, rl RateLimit) {
+		assert.Equal(t, ResourceSearch, resource)
+		calls = append(calls, rl)
+	}))
+	require.NoError(t, err)
+
+	tracker := client.rateLimits
+
+	tracker.update(ResourceSearch, RateLimit{Limit: 30, Remaining: 10, Reset: 100})
+	tracker.update(ResourceCore, RateLimit{Limit: 5000, Remaining: 1, Reset: 100})
+	assert.Empty(t, calls)
+
+	tracker.update(ResourceSearch, RateLimit{Limit: 30, Remaining: 2, Reset: 100})
+	tracker.update(ResourceSearch, RateLimit{Limit: 30, Remaining: 1, Reset: 100})
+	assert.Len(t, calls, 1, "notifies once per drop")
+
+	tracker.update(ResourceSearch, RateLimit{Limit: 30, Remaining: 5, Reset: 100})
+	assert.Equal(t, 1, client.RateLimits()[ResourceSearch].Remaining, "stale response is ignored")
+
+	tracker.update(ResourceSearch, RateLimit{Limit: 30, Remaining: 30, Reset: 200})
+	tracker.update(ResourceSearch, RateLimit{Limit: 30, Remaining: 0, Reset: 200})
+	assert.Equal(t, []RateLimit{
+		{Limit: 30, Remaining: 2, Reset: 100},
+		{Limit: 30, Remaining: 0, Reset: 200},
+	}, calls)
+}
+
+func TestWithRateLimitThreshold_Invalid(t *testing.T) {
+	_, err := NewClient(WithRateLimitThreshold(ResourceCore, 1.5, func(string, RateLimit) {}))
+	require.Error(t, err)
+
+	_, err = NewClient(WithRateLimitThreshold(ResourceCore, 0.5, nil))
+	require.Error(t, err)
+}
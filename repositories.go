@@ -1,10 +1,13 @@
 package github
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 // RepositoriesService provides access to repository-related API methods.
@@ -16,6 +19,7 @@ type RepositoriesService struct {
 // GitHub API docs: https://docs.github.com/en/rest/repos/repos
 type Repository struct {
 	ID              int64      `json:"id"`
+	NodeID          string     `json:"node_id"`
 	Name            string     `json:"name"`
 	Fullname        string     `json:"full_name"`
 	Owner           *User      `json:"owner"`
@@ -56,17 +60,17 @@ type Repository struct {
 // Get fetches a repository by its owner and name.
 // This method retrieves detailed information about a specific repository,
 // including its metadata, statistics, and permissions for the authenticated user.
-func (s *RepositoriesService) Get(ctx context.Context, owner string, repo string) (*Repository, *Response, error) {
+func (s *RepositoriesService) Get(ctx context.Context, owner string, repo string, reqOpts ...RequestOption) (*Repository, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s", owner, repo)
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	r := new(Repository)
 
-	resp, err := s.client.Do(ctx, req, r)
+	resp, err := s.client.Do(ctx, req, r, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -74,6 +78,50 @@ func (s *RepositoriesService) Get(ctx context.Context, owner string, repo string
 	return r, resp, nil
 }
 
+// GetContentsRaw fetches the raw bytes of a file in a repository. The
+// ref may name a branch, tag or commit, or be empty for the default branch.
+// GitHub API docs: https://docs.github.com/en/rest/repos/contents#get-repository-content
+func (s *RepositoriesService) GetContentsRaw(
+	ctx context.Context,
+	owner string,
+	repo string,
+	filePath string,
+	ref string,
+	reqOpts ...RequestOption,
+) ([]byte, *Response, error) {
+	path := fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, escapePath(filePath))
+
+	if ref != "" {
+		path += "?" + url.Values{"ref": []string{ref}}.Encode()
+	}
+
+	reqOpts = append(reqOpts[:len(reqOpts):len(reqOpts)], WithAccept(MediaTypeRaw))
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var buf bytes.Buffer
+
+	resp, err := s.client.Do(ctx, req, &buf, reqOpts...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return buf.Bytes(), resp, nil
+}
+
+// escapePath escapes every segment of a slash separated path.
+func escapePath(p string) string {
+	segments := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
 // RepositoryUpdateRequest represents the request body for updating a repository.
 // GitHub API docs: https://docs.github.com/en/rest/repos/repos#update-a-repository
 type RepositoryUpdateRequest struct {
@@ -111,17 +159,18 @@ func (s *RepositoriesService) Update(
 	owner string,
 	repo string,
 	body RepositoryUpdateRequest,
+	reqOpts ...RequestOption,
 ) (*Repository, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s", owner, repo)
 
-	req, err := s.client.NewRequest(http.MethodPatch, path, body)
+	req, err := s.client.NewRequest(http.MethodPatch, path, body, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	r := new(Repository)
 
-	resp, err := s.client.Do(ctx, req, r)
+	resp, err := s.client.Do(ctx, req, r, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -133,15 +182,15 @@ func (s *RepositoriesService) Update(
 // This method deletes the specified repository. This action cannot be undone,
 // and all data including issues, pull requests, and wiki pages will be lost.
 // Note that this requires admin permissions on the repository.
-func (s *RepositoriesService) Delete(ctx context.Context, owner string, repo string) (*Response, error) {
+func (s *RepositoriesService) Delete(ctx context.Context, owner string, repo string, reqOpts ...RequestOption) (*Response, error) {
 	path := fmt.Sprintf("repos/%s/%s", owner, repo)
 
-	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := s.client.NewRequest(http.MethodDelete, path, nil, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(ctx, req, nil)
+	resp, err := s.client.Do(ctx, req, nil, reqOpts...)
 	if err != nil {
 		return resp, err
 	}
@@ -182,17 +231,17 @@ type RepositoryCreateRequest struct {
 // configuration options such as description, visibility, initialization
 // settings, and merge preferences. The repository will be owned by
 // the authenticated user.
-func (s *RepositoriesService) Create(ctx context.Context, body RepositoryCreateRequest) (*Repository, *Response, error) {
+func (s *RepositoriesService) Create(ctx context.Context, body RepositoryCreateRequest, reqOpts ...RequestOption) (*Repository, *Response, error) {
 	path := "user/repos"
 
-	req, err := s.client.NewRequest(http.MethodPost, path, body)
+	req, err := s.client.NewRequest(http.MethodPost, path, body, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	repo := new(Repository)
 
-	resp, err := s.client.Do(ctx, req, repo)
+	resp, err := s.client.Do(ctx, req, repo, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -219,6 +268,7 @@ func (s *RepositoriesService) List(
 	ctx context.Context,
 	owner string,
 	opts *RepositoryListOptions,
+	reqOpts ...RequestOption,
 ) ([]*Repository, *Response, error) {
 	path := fmt.Sprintf("users/%s/repos", owner)
 
@@ -246,14 +296,14 @@ func (s *RepositoriesService) List(
 		}
 	}
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	repos := new([]*Repository)
 
-	res, err := s.client.Do(ctx, req, repos)
+	res, err := s.client.Do(ctx, req, repos, reqOpts...)
 	if err != nil {
 		return nil, res, err
 	}
@@ -261,6 +311,29 @@ func (s *RepositoriesService) List(
 	return *repos, res, nil
 }
 
+// ListAll returns an iterator over all repositories of a specific user.
+// It transparently follows pagination links, starting from the page
+// set in opts, and stops early once opts.MaxItems repositories have
+// been yielded or the context is cancelled.
+func (s *RepositoriesService) ListAll(
+	ctx context.Context,
+	owner string,
+	opts *RepositoryListOptions,
+	reqOpts ...RequestOption,
+) iter.Seq2[*Repository, error] {
+	return eachRange(func() iter.Seq2[*Repository, error] {
+		o := RepositoryListOptions{}
+		if opts != nil {
+			o = *opts
+		}
+
+		return paginate(ctx, o.ListOptions, func(ctx context.Context, lo *ListOptions) ([]*Repository, *Response, error) {
+			o.ListOptions = lo
+			return s.List(ctx, owner, &o, reqOpts...)
+		})
+	})
+}
+
 // ListContributors retrieves the list of contributors for a repository.
 // This method returns a list of users who have contributed to the specified
 // repository. You can include anonymous contributors in the results and
@@ -271,6 +344,7 @@ func (s *RepositoriesService) ListContributors(
 	owner string,
 	repo string,
 	opts *RepositoryListOptions,
+	reqOpts ...RequestOption,
 ) ([]*User, *Response, error) {
 	path := fmt.Sprintf("repos/%s/%s/contributors", owner, repo)
 
@@ -290,17 +364,41 @@ func (s *RepositoriesService) ListContributors(
 		}
 	}
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	contributors := new([]*User)
 
-	res, err := s.client.Do(ctx, req, contributors)
+	res, err := s.client.Do(ctx, req, contributors, reqOpts...)
 	if err != nil {
 		return nil, res, err
 	}
 
 	return *contributors, res, nil
 }
+
+// ListContributorsAll returns an iterator over all contributors of a repository.
+// It transparently follows pagination links, starting from the page
+// set in opts, and stops early once opts.MaxItems contributors have
+// been yielded or the context is cancelled.
+func (s *RepositoriesService) ListContributorsAll(
+	ctx context.Context,
+	owner string,
+	repo string,
+	opts *RepositoryListOptions,
+	reqOpts ...RequestOption,
+) iter.Seq2[*User, error] {
+	return eachRange(func() iter.Seq2[*User, error] {
+		o := RepositoryListOptions{}
+		if opts != nil {
+			o = *opts
+		}
+
+		return paginate(ctx, o.ListOptions, func(ctx context.Context, lo *ListOptions) ([]*User, *Response, error) {
+			o.ListOptions = lo
+			return s.ListContributors(ctx, owner, repo, &o, reqOpts...)
+		})
+	})
+}
@@ -516,3 +516,21 @@ func TestRepositoriesService_ListContributors(t *testing.T) {
 		})
 	}
 }
+
+func TestRepositoriesService_GetContentsRaw(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/octocat/hello/contents/docs/my%20file.md", r.URL.EscapedPath())
+		assert.Equal(t, "v1.0", r.URL.Query().Get("ref"))
+		assert.Equal(t, MediaTypeRaw, r.Header.Get("Accept"))
+
+		_, _ = w.Write([]byte("# Hello\n"))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	data, _, err := client.Repositories.GetContentsRaw(context.Background(), "octocat", "hello", "docs/my file.md", "v1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "# Hello\n", string(data))
+}
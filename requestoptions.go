@@ -0,0 +1,77 @@
+package github
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestOption customizes a single API call. Every service method accepts
+// request options and passes them to Client.NewRequest and Client.Do.
+type RequestOption func(*requestConfig)
+
+type requestConfig struct {
+	header  http.Header
+	noRetry bool
+	timeout time.Duration
+
+	// anonymous is set for requests to hosts other than the API, such as
+	// pre-signed download URLs, which must not be sent credentials
+	anonymous bool
+}
+
+// WithAccept overrides the Accept header of the request, for example to
+// request an alternate media type.
+func WithAccept(mediaType string) RequestOption {
+	return WithHeader("Accept", mediaType)
+}
+
+// WithHeader sets a header of the request, replacing any value set by
+// the client.
+func WithHeader(key, value string) RequestOption {
+	return func(rc *requestConfig) {
+		if rc.header == nil {
+			rc.header = http.Header{}
+		}
+
+		rc.header.Set(key, value)
+	}
+}
+
+// WithoutRetry sends the request only once, regardless of the retry
+// settings of the client.
+func WithoutRetry() RequestOption {
+	return func(rc *requestConfig) {
+		rc.noRetry = true
+	}
+}
+
+// WithTimeout limits the duration of the call, including retries and
+// reading the response body.
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(rc *requestConfig) {
+		rc.timeout = timeout
+	}
+}
+
+func newRequestConfig(opts []RequestOption) *requestConfig {
+	rc := &requestConfig{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(rc)
+		}
+	}
+
+	return rc
+}
+
+// applyHeader sets the headers of the config on req.
+func (rc *requestConfig) applyHeader(req *http.Request) {
+	if len(rc.header) == 0 {
+		return
+	}
+
+	req.Header = req.Header.Clone()
+	for key, values := range rc.header {
+		req.Header[key] = values
+	}
+}
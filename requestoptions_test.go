@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestOptions_Headers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.github.raw+json", r.Header.Get("Accept"))
+		assert.Equal(t, "2024-01-01", r.Header.Get("X-Github-Api-Version"))
+		assert.Equal(t, "yes", r.Header.Get("X-Custom"))
+
+		_, _ = w.Write([]byte(`{"number": 1}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	_, _, err = client.Issues.Get(context.Background(), "o", "r", 1,
+		WithAccept("application/vnd.github.raw+json"),
+		WithHeader("X-Github-Api-Version", "2024-01-01"),
+		WithHeader("X-Custom", "yes"),
+	)
+	require.NoError(t, err)
+}
+
+func TestRequestOptions_DoAppliesHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "yes", r.Header.Get("X-Custom"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(http.MethodGet, "user", nil)
+	require.NoError(t, err)
+
+	_, err = client.Do(context.Background(), req, nil, WithHeader("X-Custom", "yes"))
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Get("X-Custom"), "the caller's request is not modified")
+}
+
+func TestRequestOptions_WithoutRetry(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(
+		WithBaseURL(ts.URL),
+		WithRateLimitRetry(true),
+		WithRetryWaitMin(time.Millisecond),
+		WithRetryWaitMax(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.Users.Get(context.Background(), "octocat", WithoutRetry())
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "max retry attempts")
+	assert.Equal(t, 1, requests)
+
+	requests = 0
+
+	_, _, err = client.Users.Get(context.Background(), "octocat")
+	require.Error(t, err)
+	assert.Equal(t, defaultRetryMax, requests)
+}
+
+func TestRequestOptions_WithTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	_, _, err = client.Users.Get(context.Background(), "octocat", WithTimeout(20*time.Millisecond))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestRequestOptions_Iterator(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "yes", r.Header.Get("X-Custom"))
+		_, _ = w.Write([]byte(`[{"id": 1}]`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	for _, err := range client.Apps.ListInstallationsAll(context.Background(), nil, WithHeader("X-Custom", "yes")) {
+		require.NoError(t, err)
+	}
+}
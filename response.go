@@ -20,6 +20,10 @@ type Response struct {
 	// the response headers for the current request
 	*RateLimit
 
+	// Resource contains the rate limit resource the request counted
+	// against, as reported in the X-RateLimit-Resource header
+	Resource string
+
 	// PreviousPage contains the page number of the previous page
 	// of results, if available
 	PreviousPage int
@@ -34,6 +38,34 @@ type Response struct {
 	// LastPage contains the page number of the last page of results,
 	// if available
 	LastPage int
+
+	// NextPageToken contains the cursor of the next page of results
+	// for endpoints that paginate with after/before cursors
+	NextPageToken string
+
+	// PrevPageToken contains the cursor of the previous page of results
+	// for endpoints that paginate with after/before cursors
+	PrevPageToken string
+
+	// NextURL contains the raw URL of the next page of results,
+	// if available
+	NextURL string
+
+	// PrevURL contains the raw URL of the previous page of results,
+	// if available
+	PrevURL string
+
+	// RetryReason describes why the response is considered retryable,
+	// or is empty if it is not
+	RetryReason RetryReason
+
+	// FromCache reports whether the body was served from the cache
+	// after the API answered 304 Not Modified
+	FromCache bool
+
+	// EnterpriseVersion contains the version of the GitHub Enterprise
+	// Server instance that sent the response, or is empty for github.com
+	EnterpriseVersion string
 }
 
 func newResponse(httpresp *http.Response) (*Response, error) {
@@ -46,6 +78,8 @@ func newResponse(httpresp *http.Response) (*Response, error) {
 		return resp, err
 	}
 
+	resp.EnterpriseVersion = httpresp.Header.Get(enterpriseVersionHeader)
+
 	if err := populatePagination(resp); err != nil {
 		return resp, err
 	}
@@ -91,6 +125,8 @@ func populateRateLimit(resp *Response) error {
 		resp.Reset = reset
 	}
 
+	resp.Resource = resp.Header.Get(rateResourceHeader)
+
 	rawUsed := resp.Header.Get(rateUsedHeader)
 	if rawUsed != "" {
 		used, err := strconv.Atoi(rawUsed)
@@ -125,7 +161,23 @@ func populatePagination(resp *Response) error {
 			return err
 		}
 
-		page, err := strconv.Atoi(url.Query().Get("page"))
+		query := url.Query()
+
+		switch link.Rel {
+		case linkPrev:
+			resp.PrevURL = link.URL
+			resp.PrevPageToken = query.Get("before")
+		case linkNext:
+			resp.NextURL = link.URL
+			resp.NextPageToken = query.Get("after")
+		}
+
+		rawPage := query.Get("page")
+		if rawPage == "" {
+			continue
+		}
+
+		page, err := strconv.Atoi(rawPage)
 		if err != nil {
 			return err
 		}
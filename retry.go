@@ -0,0 +1,281 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryReason describes why a response is considered retryable.
+type RetryReason string
+
+const (
+	// RetryReasonRateLimit means the primary rate limit was exhausted
+	RetryReasonRateLimit RetryReason = "rate_limit"
+
+	// RetryReasonSecondaryRateLimit means a secondary (abuse) rate limit was hit
+	RetryReasonSecondaryRateLimit RetryReason = "secondary_rate_limit"
+
+	// RetryReasonServerError means the server was temporarily unavailable
+	RetryReasonServerError RetryReason = "server_error"
+
+	// RetryReasonNetworkError means the request failed with a transient
+	// network error before a response was received
+	RetryReasonNetworkError RetryReason = "network_error"
+)
+
+const (
+	retryAfterHeader = "Retry-After"
+
+	secondaryRateLimitWait = time.Minute
+)
+
+var serviceShuttedCodes = []int{
+	http.StatusForbidden,
+	http.StatusTooManyRequests,
+}
+
+var serviceUnavailableCodes = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+}
+
+var secondaryRateLimitMessages = []string{
+	"secondary rate limit",
+	"abuse detection",
+}
+
+func checkRetry(resp *Response) RetryReason {
+	if slices.Contains(serviceShuttedCodes, resp.StatusCode) {
+		switch {
+		case resp.Header.Get(retryAfterHeader) != "":
+			return RetryReasonSecondaryRateLimit
+		case resp.Header.Get(rateRemainigHeader) != "" && resp.Remaining == 0:
+			return RetryReasonRateLimit
+		case isSecondaryRateLimit(resp):
+			return RetryReasonSecondaryRateLimit
+		}
+	}
+
+	if slices.Contains(serviceUnavailableCodes, resp.StatusCode) {
+		return RetryReasonServerError
+	}
+
+	return ""
+}
+
+// isSecondaryRateLimit inspects the error message of the response.
+// The body is buffered so it can still be decoded afterwards.
+func isSecondaryRateLimit(resp *Response) bool {
+	msg := strings.ToLower(string(peekBody(resp)))
+	for _, m := range secondaryRateLimitMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isBadCredentials reports whether the API rejected the token itself,
+// as opposed to asking for a two-factor code.
+func isBadCredentials(resp *Response) bool {
+	if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get(otpHeader) != "" {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(string(peekBody(resp))), "bad credentials")
+}
+
+// peekBody reads the whole response body and replaces it with a buffered
+// copy so it can still be decoded afterwards.
+func peekBody(resp *Response) []byte {
+	if resp.Body == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err != nil {
+		return nil
+	}
+
+	return body
+}
+
+// parseRetryAfter parses the Retry-After header, which holds either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(resp *Response) (time.Duration, bool) {
+	raw := resp.Header.Get(retryAfterHeader)
+	if raw == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(raw); err == nil {
+		return max(time.Duration(secs)*time.Second, 0), true
+	}
+
+	if t, err := http.ParseTime(raw); err == nil {
+		return max(time.Until(t), 0), true
+	}
+
+	return 0, false
+}
+
+func calcBackoff(minD time.Duration, maxD time.Duration, attempt int, resp *Response) time.Duration {
+	if resp.Response != nil {
+		if wait, ok := parseRetryAfter(resp); ok {
+			return wait
+		}
+	}
+
+	if resp.RetryReason != RetryReasonServerError && resp.Reset != 0 && resp.Remaining == 0 {
+		resetTime := time.Unix(resp.Reset, 0)
+
+		return max(time.Until(resetTime), 0)
+	}
+
+	if resp.RetryReason == RetryReasonSecondaryRateLimit {
+		minD = max(minD, min(secondaryRateLimitWait, maxD))
+	}
+
+	return exponentialBackoff(minD, maxD, attempt)
+}
+
+// exponentialBackoff doubles minD for every attempt, caps it at maxD and
+// applies equal jitter, which keeps at least half of the backoff while
+// spreading out clients that failed at the same moment.
+func exponentialBackoff(minD time.Duration, maxD time.Duration, attempt int) time.Duration {
+	backoff := float64(minD) * math.Pow(2, float64(attempt))
+	wait := min(time.Duration(backoff), maxD)
+
+	half := wait / 2
+	if half > 0 {
+		wait = half + time.Duration(rand.Int64N(int64(half)+1))
+	}
+
+	return max(wait, min(minD, maxD))
+}
+
+// RetryPolicy reports whether a request should be retried. It is called
+// with the retryable response, or with the transport error if no response
+// was received. The policy is only consulted when retries are enabled
+// with WithRateLimitRetry.
+type RetryPolicy func(req *http.Request, resp *Response, err error) bool
+
+// DefaultRetryPolicy always retries rate limited requests, since GitHub
+// rejected them without processing, but retries server and transient
+// network errors only for idempotent requests, where repeating a request
+// that may already have been applied is safe. Like in net/http, a request
+// is idempotent if its method is or if it has an Idempotency-Key or
+// X-Idempotency-Key header, which may be nil to mark it without sending
+// the header. GraphQL queries are marked this way.
+func DefaultRetryPolicy(req *http.Request, resp *Response, err error) bool {
+	if err != nil {
+		return isTransientError(err) && isIdempotent(req)
+	}
+
+	switch resp.RetryReason {
+	case RetryReasonRateLimit, RetryReasonSecondaryRateLimit:
+		return true
+	case RetryReasonServerError:
+		return isIdempotent(req)
+	}
+
+	return false
+}
+
+// AggressiveRetryPolicy retries every rate limited, server and transient
+// network error regardless of the request method. Use it only when the
+// mutating calls made through the client are safe to repeat.
+func AggressiveRetryPolicy(req *http.Request, resp *Response, err error) bool {
+	if err != nil {
+		return isTransientError(err)
+	}
+
+	return resp.RetryReason != ""
+}
+
+var idempotentMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPut,
+	http.MethodDelete,
+}
+
+func isIdempotent(req *http.Request) bool {
+	_, hasKey := req.Header["Idempotency-Key"]
+	_, hasXKey := req.Header["X-Idempotency-Key"]
+
+	return hasKey || hasXKey || slices.Contains(idempotentMethods, req.Method)
+}
+
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// prepareBody makes sure the request body can be replayed on retry by
+// buffering it when the request has no GetBody function.
+func prepareBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to buffer request body: %w", err)
+	}
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+
+	return nil
+}
+
+// rewindBody replaces the consumed request body with a fresh copy.
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Errorf("failed to rewind request body: %w", err)
+	}
+
+	req.Body = body
+
+	return nil
+}
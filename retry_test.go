@@ -0,0 +1,199 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo_RetryReplaysBody(t *testing.T) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if len(bodies) == 1 {
+			w.Header().Set(rateRemainigHeader, "4000")
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, `{"message": "You have exceeded a secondary rate limit."}`, http.StatusForbidden)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1, "title": "New Issue"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithRateLimitRetry(true))
+	require.NoError(t, err)
+
+	issue, _, err := client.Issues.Create(context.Background(), "octocat", "Hello-World", &IssueCreateRequest{Title: "New Issue"})
+	require.NoError(t, err)
+	assert.Equal(t, "New Issue", issue.Title)
+
+	require.Len(t, bodies, 2)
+	assert.Equal(t, bodies[0], bodies[1])
+	assert.Contains(t, bodies[1], `"title":"New Issue"`)
+}
+
+func TestDo_RetryPolicy(t *testing.T) {
+	tests := []struct {
+		name             string
+		policy           RetryPolicy
+		method           string
+		expectedAttempts int32
+	}{
+		{
+			name:             "default retries idempotent method",
+			method:           http.MethodPut,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "default does not retry mutating method",
+			method:           http.MethodPost,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "aggressive retries mutating method",
+			policy:           AggressiveRetryPolicy,
+			method:           http.MethodPost,
+			expectedAttempts: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				w.WriteHeader(http.StatusBadGateway)
+			}))
+			defer ts.Close()
+
+			opts := []option{
+				WithBaseURL(ts.URL),
+				WithRateLimitRetry(true),
+				WithRetryMax(3),
+				WithRetryWaitMin(time.Millisecond),
+				WithRetryWaitMax(time.Millisecond),
+			}
+			if tt.policy != nil {
+				opts = append(opts, WithRetryPolicy(tt.policy))
+			}
+
+			client, err := NewClient(opts...)
+			require.NoError(t, err)
+
+			req, err := client.NewRequest(tt.method, "repos/octocat/Hello-World/pulls/1/merge", &MergeRequest{Sha: "abc"})
+			require.NoError(t, err)
+
+			_, err = client.Do(context.Background(), req, nil)
+			require.Error(t, err)
+
+			assert.Equal(t, tt.expectedAttempts, attempts.Load())
+		})
+	}
+}
+
+func TestDo_ForbiddenWithoutRateLimitIsNotRetried(t *testing.T) {
+	var attempts atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		http.Error(w, `{"message": "Must have admin rights to Repository."}`, http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithRateLimitRetry(true), WithRetryMax(3))
+	require.NoError(t, err)
+
+	_, _, err = client.Repositories.Get(context.Background(), "octocat", "Hello-World")
+	require.Error(t, err)
+
+	var rateLimitErr *RateLimitError
+	assert.False(t, errors.As(err, &rateLimitErr))
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestDo_RetryNetworkError(t *testing.T) {
+	var attempts atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(
+		WithBaseURL(ts.URL),
+		WithHTTPClient(&http.Client{Transport: &http.Transport{DisableKeepAlives: true}}),
+		WithRateLimitRetry(true),
+		WithRetryWaitMin(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	user, _, err := client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+
+	assert.Equal(t, "octocat", user.Login)
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, expected: true},
+		{name: "timeout", err: &timeoutError{}, expected: true},
+		{name: "context canceled", err: context.Canceled, expected: false},
+		{name: "other", err: errors.New("tls: bad certificate"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, isTransientError(tt.err))
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (e *timeoutError) Error() string   { return "i/o timeout" }
+func (e *timeoutError) Timeout() bool   { return true }
+func (e *timeoutError) Temporary() bool { return true }
+
+func TestPrepareBody_BuffersReader(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://api.github.com/user", io.NopCloser(strings.NewReader("payload")))
+	require.NoError(t, err)
+	require.Nil(t, req.GetBody)
+
+	require.NoError(t, prepareBody(req))
+
+	first, _ := io.ReadAll(req.Body)
+	require.NoError(t, rewindBody(req))
+	second, _ := io.ReadAll(req.Body)
+
+	assert.Equal(t, "payload", string(first))
+	assert.Equal(t, "payload", string(second))
+}
@@ -0,0 +1,239 @@
+package github
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Priority is the scheduling priority of a request. Requests with a
+// higher priority are admitted first when a Scheduler is busy.
+type Priority int
+
+const (
+	// PriorityLow is meant for background work that may be delayed
+	// arbitrarily, such as periodic synchronization
+	PriorityLow Priority = iota - 1
+
+	// PriorityNormal is the priority of requests whose context does not
+	// carry one
+	PriorityNormal
+
+	// PriorityHigh is meant for interactive requests. Only high-priority
+	// requests may consume the quota reserve of a Scheduler
+	PriorityHigh
+)
+
+type priorityKey struct{}
+
+// ContextWithPriority returns a copy of ctx that carries the given
+// request priority.
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority carried by ctx, or
+// PriorityNormal if it carries none.
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+
+	return PriorityNormal
+}
+
+// Scheduler admits requests of a client by priority. It bounds the number
+// of requests in flight and keeps a reserve of the remaining quota of every
+// rate limit resource that only high-priority requests may consume, so that
+// background work cannot starve interactive requests. Waiting requests are
+// admitted highest priority first and in arrival order within a priority.
+// A Scheduler is safe for concurrent use.
+type Scheduler struct {
+	mu          sync.Mutex
+	maxInFlight int
+	reserve     int
+	inFlight    int
+	waiters     []*schedWaiter
+	quotas      map[string]*schedQuota
+}
+
+type schedWaiter struct {
+	priority Priority
+	resource string
+	ready    chan struct{}
+}
+
+type schedQuota struct {
+	remaining int
+	reset     time.Time
+}
+
+// NewScheduler creates a scheduler that lets at most maxInFlight requests
+// run at once and holds back the last reserve requests of each resource's
+// quota for high-priority requests. A maxInFlight below one means no limit.
+func NewScheduler(maxInFlight int, reserve int) *Scheduler {
+	return &Scheduler{
+		maxInFlight: maxInFlight,
+		reserve:     max(reserve, 0),
+		quotas:      make(map[string]*schedQuota),
+	}
+}
+
+// InFlight returns the number of requests currently admitted.
+func (s *Scheduler) InFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.inFlight
+}
+
+// Queued returns the number of requests waiting to be admitted.
+func (s *Scheduler) Queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.waiters)
+}
+
+// acquire blocks until a request with the given priority against resource
+// is admitted or ctx is done. On success the returned function must be
+// called once the request has finished.
+func (s *Scheduler) acquire(ctx context.Context, p Priority, resource string) (func(), error) {
+	w := &schedWaiter{priority: p, resource: resource, ready: make(chan struct{})}
+
+	s.mu.Lock()
+	s.enqueue(w)
+	s.dispatch(time.Now())
+	reset := s.blockedUntil(w)
+	s.mu.Unlock()
+
+	for {
+		var wake <-chan time.Time
+		if !reset.IsZero() {
+			wake = time.After(time.Until(reset))
+		}
+
+		select {
+		case <-w.ready:
+			return s.release, nil
+		case <-wake:
+			s.mu.Lock()
+			s.dispatch(time.Now())
+			reset = s.blockedUntil(w)
+			s.mu.Unlock()
+		case <-ctx.Done():
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			select {
+			case <-w.ready:
+				// admitted concurrently, hand the slot to the next waiter
+				s.inFlight--
+			default:
+				s.remove(w)
+			}
+
+			s.dispatch(time.Now())
+
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (s *Scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inFlight--
+	s.dispatch(time.Now())
+}
+
+// update records the rate limit reported for resource and admits requests
+// that were waiting for the quota to reset.
+func (s *Scheduler) update(resource string, rl *RateLimit) {
+	if rl == nil || rl.Reset == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.quotas[resource] = &schedQuota{remaining: rl.Remaining, reset: time.Unix(rl.Reset, 0)}
+	s.dispatch(time.Now())
+}
+
+// enqueue inserts w after all waiters with the same or a higher priority.
+func (s *Scheduler) enqueue(w *schedWaiter) {
+	i := len(s.waiters)
+	for i > 0 && s.waiters[i-1].priority < w.priority {
+		i--
+	}
+
+	s.waiters = append(s.waiters, nil)
+	copy(s.waiters[i+1:], s.waiters[i:])
+	s.waiters[i] = w
+}
+
+func (s *Scheduler) remove(w *schedWaiter) {
+	for i, other := range s.waiters {
+		if other == w {
+			s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// dispatch admits waiters in queue order while there are free slots.
+// Waiters held back by the quota reserve are skipped, so they don't
+// block requests against other resources or with a higher priority.
+func (s *Scheduler) dispatch(now time.Time) {
+	kept := s.waiters[:0]
+
+	for _, w := range s.waiters {
+		if (s.maxInFlight > 0 && s.inFlight >= s.maxInFlight) || !s.allowed(w, now) {
+			kept = append(kept, w)
+			continue
+		}
+
+		s.inFlight++
+
+		if q, ok := s.quotas[w.resource]; ok && q.remaining > 0 {
+			q.remaining--
+		}
+
+		close(w.ready)
+	}
+
+	clear(s.waiters[len(kept):])
+	s.waiters = kept
+}
+
+// allowed reports whether the quota of w's resource may be spent on w.
+func (s *Scheduler) allowed(w *schedWaiter, now time.Time) bool {
+	if w.priority >= PriorityHigh {
+		return true
+	}
+
+	q, ok := s.quotas[w.resource]
+	if !ok || !now.Before(q.reset) {
+		return true
+	}
+
+	return q.remaining > s.reserve
+}
+
+// blockedUntil returns when the quota reserve that holds back w is lifted,
+// or the zero time if w is not held back by it.
+func (s *Scheduler) blockedUntil(w *schedWaiter) time.Time {
+	select {
+	case <-w.ready:
+		return time.Time{}
+	default:
+	}
+
+	if s.allowed(w, time.Now()) {
+		return time.Time{}
+	}
+
+	return s.quotas[w.resource].reset
+}
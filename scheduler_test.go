@@ -0,0 +1,207 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityFromContext(t *testing.T) {
+	assert.Equal(t, PriorityNormal, PriorityFromContext(context.Background()))
+	assert.Equal(t, PriorityHigh, PriorityFromContext(ContextWithPriority(context.Background(), PriorityHigh)))
+}
+
+func TestScheduler_PriorityOrder(t *testing.T) {
+	s := NewScheduler(1, 0)
+
+	release, err := s.acquire(context.Background(), PriorityNormal, ResourceCore)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var order []Priority
+	var wg sync.WaitGroup
+
+	for i, p := range []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityLow} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			done, err := s.acquire(context.Background(), p, ResourceCore)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			order = append(order, p)
+			mu.Unlock()
+
+			done()
+		}()
+
+		require.Eventually(t, func() bool { return s.Queued() == i+1 }, time.Second, time.Millisecond)
+	}
+
+	release()
+	wg.Wait()
+
+	assert.Equal(t, []Priority{PriorityHigh, PriorityNormal, PriorityLow, PriorityLow}, order)
+	assert.Equal(t, 0, s.InFlight())
+}
+
+func TestScheduler_Reserve(t *testing.T) {
+	s := NewScheduler(0, 5)
+	s.update(ResourceCore, &RateLimit{Remaining: 5, Reset: time.Now().Add(time.Hour).Unix()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := s.acquire(ctx, PriorityNormal, ResourceCore)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, s.Queued())
+
+	release, err := s.acquire(context.Background(), PriorityHigh, ResourceCore)
+	require.NoError(t, err)
+	release()
+
+	release, err = s.acquire(context.Background(), PriorityLow, ResourceSearch)
+	require.NoError(t, err, "the reserve of core does not hold back search")
+	release()
+}
+
+func TestScheduler_ReserveLiftedByUpdate(t *testing.T) {
+	s := NewScheduler(0, 5)
+	s.update(ResourceCore, &RateLimit{Remaining: 0, Reset: time.Now().Add(time.Hour).Unix()})
+
+	admitted := make(chan error, 1)
+	go func() {
+		release, err := s.acquire(context.Background(), PriorityLow, ResourceCore)
+		if err == nil {
+			release()
+		}
+		admitted <- err
+	}()
+
+	require.Eventually(t, func() bool { return s.Queued() == 1 }, time.Second, time.Millisecond)
+
+	s.update(ResourceCore, &RateLimit{Remaining: 5000, Reset: time.Now().Add(time.Hour).Unix()})
+
+	select {
+	case err := <-admitted:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("waiter was not admitted after the quota was replenished")
+	}
+}
+
+func TestDo_SchedulerMaxInFlight(t *testing.T) {
+	var current, peak atomic.Int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := current.Add(1)
+		defer current.Add(-1)
+
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithScheduler(NewScheduler(2, 0)))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, _, err := client.Users.Get(context.Background(), "octocat")
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
+
+func TestDo_SchedulerReserve(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(rateLimitHeader, "5000")
+		w.Header().Set(rateRemainigHeader, "10")
+		w.Header().Set(rateResetHeader, reset)
+		w.Header().Set(rateResourceHeader, ResourceCore)
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithScheduler(NewScheduler(0, 10)))
+	require.NoError(t, err)
+
+	_, _, err = client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(ContextWithPriority(context.Background(), PriorityLow), 20*time.Millisecond)
+	defer cancel()
+
+	_, _, err = client.Users.Get(ctx, "octocat")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, _, err = client.Users.Get(ContextWithPriority(context.Background(), PriorityHigh), "octocat")
+	require.NoError(t, err)
+}
+
+func TestDo_SchedulerReleasesSlotWhileWaitingToRetry(t *testing.T) {
+	var limited atomic.Bool
+	hit := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/limited" && limited.CompareAndSwap(false, true) {
+			w.Header().Set(retryAfterHeader, "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			close(hit)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer ts.Close()
+
+	scheduler := NewScheduler(1, 0)
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithRateLimitRetry(true), WithScheduler(scheduler))
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := client.Users.Get(context.Background(), "limited")
+		done <- err
+	}()
+
+	<-hit
+
+	start := time.Now()
+	_, _, err = client.Users.Get(ContextWithPriority(context.Background(), PriorityHigh), "octocat")
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "a request waiting to be retried must not hold its slot")
+
+	require.NoError(t, <-done)
+	assert.Equal(t, 0, scheduler.InFlight())
+}
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"iter"
 	"net/http"
 	"net/url"
 	"strings"
@@ -35,7 +36,7 @@ type SearchOptions struct {
 // syntax. You can filter by various criteria such as language, stars,
 // forks, and more. The results can be sorted and paginated using
 // the SearchOptions parameter.
-func (s *SearchService) Repositories(ctx context.Context, sq string, opts *SearchOptions) (*Search[Repository], *Response, error) {
+func (s *SearchService) Repositories(ctx context.Context, sq string, opts *SearchOptions, reqOpts ...RequestOption) (*Search[Repository], *Response, error) {
 	path := "search/repositories"
 
 	v := url.Values{}
@@ -64,14 +65,14 @@ func (s *SearchService) Repositories(ctx context.Context, sq string, opts *Searc
 		path += "?" + buildSearchParams(sq)
 	}
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	search := new(Search[Repository])
 	
-	resp, err := s.client.Do(ctx, req, search)
+	resp, err := s.client.Do(ctx, req, search, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -79,12 +80,36 @@ func (s *SearchService) Repositories(ctx context.Context, sq string, opts *Searc
 	return search, resp, nil
 }
 
+// RepositoriesAll returns an iterator over all repositories matching the query.
+// It transparently follows pagination links, starting from the page
+// set in opts, and stops early once opts.MaxItems repositories have
+// been yielded or the context is cancelled.
+func (s *SearchService) RepositoriesAll(ctx context.Context, sq string, opts *SearchOptions, reqOpts ...RequestOption) iter.Seq2[*Repository, error] {
+	return eachRange(func() iter.Seq2[*Repository, error] {
+		o := SearchOptions{}
+		if opts != nil {
+			o = *opts
+		}
+
+		return paginate(ctx, o.ListOptions, func(ctx context.Context, lo *ListOptions) ([]*Repository, *Response, error) {
+			o.ListOptions = lo
+
+			search, resp, err := s.Repositories(ctx, sq, &o, reqOpts...)
+			if err != nil {
+				return nil, resp, err
+			}
+
+			return search.Items, resp, nil
+		})
+	})
+}
+
 // Users searches for users based on the provided query.
 // This method allows you to search for GitHub users using various
 // search criteria such as username, full name, location, and followers.
 // The results can be sorted by different fields and paginated using
 // the SearchOptions parameter.
-func (s *SearchService) Users(ctx context.Context, sq string, opts *SearchOptions) (*Search[User], *Response, error) {
+func (s *SearchService) Users(ctx context.Context, sq string, opts *SearchOptions, reqOpts ...RequestOption) (*Search[User], *Response, error) {
 	path := "search/users"
 
 	v := url.Values{}
@@ -113,14 +138,14 @@ func (s *SearchService) Users(ctx context.Context, sq string, opts *SearchOption
 		path += "?" + buildSearchParams(sq)
 	}
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	search := new(Search[User])
 
-	resp, err := s.client.Do(ctx, req, search)
+	resp, err := s.client.Do(ctx, req, search, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -128,6 +153,30 @@ func (s *SearchService) Users(ctx context.Context, sq string, opts *SearchOption
 	return search, resp, nil
 }
 
+// UsersAll returns an iterator over all users matching the query.
+// It transparently follows pagination links, starting from the page
+// set in opts, and stops early once opts.MaxItems users have been
+// yielded or the context is cancelled.
+func (s *SearchService) UsersAll(ctx context.Context, sq string, opts *SearchOptions, reqOpts ...RequestOption) iter.Seq2[*User, error] {
+	return eachRange(func() iter.Seq2[*User, error] {
+		o := SearchOptions{}
+		if opts != nil {
+			o = *opts
+		}
+
+		return paginate(ctx, o.ListOptions, func(ctx context.Context, lo *ListOptions) ([]*User, *Response, error) {
+			o.ListOptions = lo
+
+			search, resp, err := s.Users(ctx, sq, &o, reqOpts...)
+			if err != nil {
+				return nil, resp, err
+			}
+
+			return search.Items, resp, nil
+		})
+	})
+}
+
 func buildSearchParams(s string) string {
 	trimmed := strings.TrimSpace(s)
 	chars := strings.Split(trimmed, " ")
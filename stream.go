@@ -0,0 +1,345 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// maxRedirects is the maximum number of redirects BareDo follows.
+const maxRedirects = 10
+
+type noRedirectKey struct{}
+
+// noRedirectClient returns a copy of client that returns redirect
+// responses instead of following them.
+func noRedirectClient(client *http.Client) *http.Client {
+	nr := *client
+	nr.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &nr
+}
+
+// BareDo sends an API request and returns the response with its body
+// left open, so that large downloads such as archives, logs and release
+// assets can be streamed. The request is authenticated and retried like
+// with Do. Redirects are followed; when a redirect leads to another host,
+// such as a pre-signed storage URL, the credentials are not sent along.
+// A request with a Range header resumes a download from an offset.
+//
+// The caller must close the response body. Responses with a 4xx or 5xx
+// status are returned as errors with their body already closed.
+func (c *Client) BareDo(ctx context.Context, req *http.Request, opts ...RequestOption) (*Response, error) {
+	rc := newRequestConfig(opts)
+
+	cancel := context.CancelFunc(func() {})
+	if rc.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, rc.timeout)
+	}
+
+	resp, err := c.instrument(ctx, req, func(ctx context.Context) (*Response, error) {
+		return c.bareDo(ctx, req, rc)
+	})
+	if err != nil {
+		cancel()
+		return resp, err
+	}
+
+	resp.Body = &closeHookBody{ReadCloser: resp.Body, onClose: cancel}
+
+	return resp, nil
+}
+
+// bareDo implements BareDo without instrumentation.
+func (c *Client) bareDo(ctx context.Context, req *http.Request, rc *requestConfig) (*Response, error) {
+	ctx = context.WithValue(ctx, noRedirectKey{}, true)
+
+	for redirects := 0; ; redirects++ {
+		resp, err := c.send(ctx, req, rc, false)
+		if err != nil {
+			return resp, err
+		}
+
+		location := resp.Header.Get("Location")
+		if !isRedirect(resp.StatusCode) || location == "" {
+			if resp.StatusCode >= 400 {
+				err = newError(resp)
+				_ = resp.Body.Close()
+
+				return resp, err
+			}
+
+			return resp, nil
+		}
+
+		_ = resp.Body.Close()
+
+		if redirects >= maxRedirects {
+			return resp, fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+
+		next, err := redirectRequest(req, resp, location)
+		if err != nil {
+			return resp, err
+		}
+
+		if next.URL.Host != req.URL.Host {
+			rc = &requestConfig{noRetry: rc.noRetry, anonymous: true}
+		}
+
+		req = next
+	}
+}
+
+// send sends a single request, authenticating it unless it is anonymous,
+// and returns the response with its body left open. If cache is set, GET
+// requests are revalidated against the cache of the client.
+func (c *Client) send(ctx context.Context, req *http.Request, rc *requestConfig, cache bool) (*Response, error) {
+	req = req.WithContext(ctx)
+	rc.applyHeader(req)
+
+	fromSource := false
+	if !rc.anonymous {
+		var err error
+
+		fromSource, err = c.authorize(ctx, req, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := prepareBody(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.exchange(ctx, req, rc, cache)
+	if err == nil && fromSource {
+		resp, err = c.refreshOnBadCredentials(ctx, req, rc, cache, resp)
+	}
+
+	return resp, err
+}
+
+// exchange sends req with roundTrip. If cache is set, it is revalidated
+// against the cache entry of the credentials it carries. req is left
+// unchanged, so that it can be resent with other credentials.
+func (c *Client) exchange(ctx context.Context, req *http.Request, rc *requestConfig, cache bool) (*Response, error) {
+	req = req.WithContext(ctx)
+
+	var cacheKey string
+	var cached *CacheEntry
+	if cache {
+		cacheKey, cached = c.prepareCache(req)
+	}
+
+	resp, err := c.roundTrip(ctx, req, rc)
+	if err == nil && cache {
+		err = c.applyCache(cacheKey, cached, resp)
+	}
+
+	return resp, err
+}
+
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+// redirectRequest creates the request that follows a redirect to location.
+// Like net/http, it keeps the method and body only for 307 and 308
+// redirects. Credentials are dropped when the redirect leaves the host.
+func redirectRequest(req *http.Request, resp *Response, location string) (*http.Request, error) {
+	u, err := req.URL.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redirect location %q: %w", location, err)
+	}
+
+	method := req.Method
+	var body io.ReadCloser
+
+	switch {
+	case resp.StatusCode == http.StatusTemporaryRedirect || resp.StatusCode == http.StatusPermanentRedirect:
+		if req.GetBody != nil {
+			if body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	case method != http.MethodHead:
+		method = http.MethodGet
+	}
+
+	next, err := http.NewRequestWithContext(req.Context(), method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		next.GetBody = req.GetBody
+		next.ContentLength = req.ContentLength
+	}
+
+	next.Header = req.Header.Clone()
+
+	if u.Host != req.URL.Host {
+		next.Header.Del("Authorization")
+		next.Header.Del("Cookie")
+	}
+
+	if body == nil {
+		next.Header.Del("Content-Type")
+	}
+
+	return next, nil
+}
+
+// DoStream sends an API request like BareDo and returns its body as a
+// stream. If reading the body fails midway with a transient network
+// error, the stream transparently resumes the download with a Range
+// request from the last byte read, using If-Range so that a changed
+// resource is never stitched together; reading then fails instead. The
+// caller must close the stream.
+func (c *Client) DoStream(ctx context.Context, req *http.Request, opts ...RequestOption) (io.ReadCloser, *Response, error) {
+	resp, err := c.BareDo(ctx, req, opts...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	stream := &resumableBody{
+		client:    c,
+		ctx:       ctx,
+		req:       req,
+		opts:      opts,
+		body:      resp.Body,
+		validator: rangeValidator(resp),
+		resumable: req.Header.Get("Range") == "" && req.Method == http.MethodGet,
+	}
+
+	return stream, resp, nil
+}
+
+// rangeValidator returns the value for If-Range that guarantees a resumed
+// download continues the same representation, or an empty string if the
+// response has no suitable validator.
+func rangeValidator(resp *Response) string {
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+
+	return resp.Header.Get("Last-Modified")
+}
+
+// resumableBody is a response body that resumes the download with
+// a Range request when reading fails with a transient error.
+type resumableBody struct {
+	client *Client
+	ctx    context.Context
+	req    *http.Request
+	opts   []RequestOption
+
+	body      io.ReadCloser
+	offset    int64
+	validator string
+	resumable bool
+	resumes   int
+	closed    bool
+}
+
+func (r *resumableBody) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, errors.New("read from closed stream")
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+
+	if err == nil || err == io.EOF || !r.canResume(err) {
+		return n, err
+	}
+
+	if resumeErr := r.resume(); resumeErr != nil {
+		return n, errors.Join(err, resumeErr)
+	}
+
+	return n, nil
+}
+
+func (r *resumableBody) canResume(err error) bool {
+	return r.resumable &&
+		r.validator != "" &&
+		r.resumes < max(r.client.retryMax, 1) &&
+		r.ctx.Err() == nil &&
+		isTransientError(err)
+}
+
+// resume reopens the download at the current offset.
+func (r *resumableBody) resume() error {
+	_ = r.body.Close()
+	r.resumes++
+
+	req := r.req.Clone(r.ctx)
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", r.offset))
+	req.Header.Set("If-Range", r.validator)
+
+	resp, err := r.client.BareDo(r.ctx, req, r.opts...)
+	if err != nil {
+		r.body = http.NoBody
+		return fmt.Errorf("failed to resume download at byte %d: %w", r.offset, err)
+	}
+
+	r.body = resp.Body
+
+	if resp.StatusCode == http.StatusPartialContent {
+		return nil
+	}
+
+	// under If-Range a full response means the resource changed, unless it
+	// still carries the same validator and the server just ignored the range
+	if rangeValidator(resp) != r.validator {
+		_ = r.body.Close()
+		r.body = http.NoBody
+
+		return fmt.Errorf("failed to resume download at byte %d: resource changed", r.offset)
+	}
+
+	if _, err := io.CopyN(io.Discard, r.body, r.offset); err != nil {
+		return fmt.Errorf("failed to resume download at byte %d: %w", r.offset, err)
+	}
+
+	return nil
+}
+
+func (r *resumableBody) Close() error {
+	if r.closed {
+		return nil
+	}
+
+	r.closed = true
+
+	return r.body.Close()
+}
+
+// closeHookBody calls onClose once when the body is closed.
+type closeHookBody struct {
+	io.ReadCloser
+
+	once    sync.Once
+	onClose func()
+}
+
+func (b *closeHookBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.onClose)
+
+	return err
+}
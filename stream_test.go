@@ -0,0 +1,237 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBareDo(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte("archive contents"))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithToken("test-token"))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(http.MethodGet, "repos/o/r/tarball/main", nil)
+	require.NoError(t, err)
+
+	resp, err := client.BareDo(context.Background(), req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "archive contents", string(body))
+}
+
+func TestBareDo_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(http.MethodGet, "repos/o/r/tarball/main", nil)
+	require.NoError(t, err)
+
+	_, err = client.BareDo(context.Background(), req)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestBareDo_Redirect(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"), "credentials are not sent to another host")
+		assert.Equal(t, "sig=abc", r.URL.RawQuery)
+		_, _ = w.Write([]byte("log contents"))
+	}))
+	defer storage.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		switch r.URL.Path {
+		case "/repos/o/r/actions/runs/1/logs":
+			http.Redirect(w, r, "/redirected", http.StatusFound)
+		case "/redirected":
+			http.Redirect(w, r, storage.URL+"/logs?sig=abc", http.StatusFound)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithToken("test-token"))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(http.MethodGet, "repos/o/r/actions/runs/1/logs", nil)
+	require.NoError(t, err)
+
+	resp, err := client.BareDo(context.Background(), req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "log contents", string(body))
+}
+
+func TestBareDo_TooManyRedirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path, http.StatusFound)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(http.MethodGet, "loop", nil)
+	require.NoError(t, err)
+
+	_, err = client.BareDo(context.Background(), req)
+	require.ErrorContains(t, err, "stopped after 10 redirects")
+}
+
+func TestBareDo_Timeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(http.MethodGet, "download", nil)
+	require.NoError(t, err)
+
+	resp, err := client.BareDo(context.Background(), req, WithTimeout(20*time.Millisecond))
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDoStream_Resume(t *testing.T) {
+	const content = "0123456789abcdefghij"
+
+	tests := []struct {
+		name         string
+		ignoresRange bool
+		changed      bool
+	}{
+		{name: "partial content"},
+		{name: "full content", ignoresRange: true},
+		{name: "changed content", changed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ranges []string
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `"v1"`)
+				ranges = append(ranges, r.Header.Get("Range"))
+
+				if len(ranges) == 1 {
+					// announce the whole content but drop the connection midway
+					w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+					_, _ = w.Write([]byte(content[:8]))
+
+					return
+				}
+
+				assert.Equal(t, `"v1"`, r.Header.Get("If-Range"))
+
+				if tt.changed {
+					w.Header().Set("ETag", `"v2"`)
+					_, _ = w.Write([]byte("ABCDEFGHIJKLMNOPQRST"))
+
+					return
+				}
+
+				if tt.ignoresRange {
+					_, _ = w.Write([]byte(content))
+					return
+				}
+
+				var offset int
+				_, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &offset)
+				assert.NoError(t, err)
+
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(content)-1, len(content)))
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = w.Write([]byte(content[offset:]))
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(WithBaseURL(ts.URL))
+			require.NoError(t, err)
+
+			req, err := client.NewRequest(http.MethodGet, "download", nil)
+			require.NoError(t, err)
+
+			stream, _, err := client.DoStream(context.Background(), req)
+			require.NoError(t, err)
+
+			defer stream.Close()
+
+			body, err := io.ReadAll(stream)
+			assert.Equal(t, []string{"", "bytes=8-"}, ranges)
+
+			if tt.changed {
+				require.ErrorContains(t, err, "resource changed")
+				assert.Equal(t, content[:8], string(body), "no bytes of the new version are returned")
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, content, string(body))
+		})
+	}
+}
+
+func TestDoStream_NoValidator(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "20")
+		_, _ = w.Write([]byte("01234567"))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(http.MethodGet, "download", nil)
+	require.NoError(t, err)
+
+	stream, _, err := client.DoStream(context.Background(), req)
+	require.NoError(t, err)
+
+	defer stream.Close()
+
+	_, err = io.ReadAll(stream)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF, "a download without a validator is not resumed")
+}
@@ -1,6 +1,7 @@
 package github
 
 import (
+	"strconv"
 	"time"
 )
 
@@ -26,6 +27,7 @@ func (t *Timestamp) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
 // It parses a JSON string in RFC3339 format into the Timestamp.
+// A JSON number is read as Unix seconds, which some webhook payloads use.
 // If the JSON value is null, it sets the Timestamp to the zero time.
 func (t *Timestamp) UnmarshalJSON(data []byte) error {
 	if string(data) == "null" {
@@ -33,6 +35,16 @@ func (t *Timestamp) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
+	if len(data) > 0 && data[0] != '"' {
+		sec, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return err
+		}
+
+		t.Time = time.Unix(sec, 0).UTC()
+		return nil
+	}
+
 	s := string(data[1 : len(data)-1])
 	
 	parsed, err := time.Parse(time.RFC3339, s)
@@ -83,6 +83,12 @@ func TestTimestamp_UnmarshalJSON(t *testing.T) {
 			expectedTime: time.Date(2023, 12, 31, 23, 59, 59, 123456789, time.UTC),
 			expectError:  false,
 		},
+		{
+			name:         "Unix seconds",
+			jsonData:     `1672574400`,
+			expectedTime: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+			expectError:  false,
+		},
 		{
 			name:        "Invalid time format",
 			jsonData:    `"2023-01-01 12:00:00"`,
@@ -0,0 +1,188 @@
+package github
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenPool load-balances requests across several tokens. For every
+// request it picks the token with the most remaining quota for the
+// request's rate limit resource and skips exhausted tokens until their
+// limit resets. A TokenPool is safe for concurrent use.
+type TokenPool struct {
+	mu     sync.Mutex
+	tokens []*pooledToken
+}
+
+type pooledToken struct {
+	token       string
+	requests    int
+	rateLimited int
+	resources   map[string]*RateLimit
+}
+
+// TokenStats reports the usage of a single token in a TokenPool.
+type TokenStats struct {
+	// Token is a masked form of the token, safe to log
+	Token string
+
+	// Requests is the number of requests sent with the token
+	Requests int
+
+	// RateLimited is the number of responses that reported the
+	// token's rate limit as exhausted
+	RateLimited int
+
+	// Resources contains the last known rate limit of the token for
+	// each resource it has been used with
+	Resources map[string]RateLimit
+}
+
+// NewTokenPool creates a pool from the given tokens. Empty tokens are ignored.
+func NewTokenPool(tokens ...string) *TokenPool {
+	pool := &TokenPool{}
+
+	for _, token := range tokens {
+		if token == "" {
+			continue
+		}
+
+		pool.tokens = append(pool.tokens, &pooledToken{
+			token:     token,
+			resources: make(map[string]*RateLimit),
+		})
+	}
+
+	return pool
+}
+
+// Len returns the number of tokens in the pool.
+func (p *TokenPool) Len() int {
+	return len(p.tokens)
+}
+
+// Stats returns the usage statistics of every token in the pool.
+func (p *TokenPool) Stats() []TokenStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]TokenStats, 0, len(p.tokens))
+	for _, t := range p.tokens {
+		resources := make(map[string]RateLimit, len(t.resources))
+		for name, rl := range t.resources {
+			resources[name] = *rl
+		}
+
+		stats = append(stats, TokenStats{
+			Token:       maskToken(t.token),
+			Requests:    t.requests,
+			RateLimited: t.rateLimited,
+			Resources:   resources,
+		})
+	}
+
+	return stats
+}
+
+// acquire picks the token to use for a request against resource and
+// returns its index. Tokens that have not been used with the resource yet
+// are preferred, so the pool learns their quota. The remaining quota of
+// the chosen token is decremented right away so that concurrent callers
+// spread across tokens before their responses arrive.
+func (p *TokenPool) acquire(resource string) (int, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now().Unix()
+
+	best := -1
+	bestRemaining := -1
+	soonest := -1
+	soonestReset := int64(math.MaxInt64)
+
+	for i, t := range p.tokens {
+		remaining := math.MaxInt
+
+		if rl, ok := t.resources[resource]; ok {
+			if rl.Reset <= now {
+				remaining = max(rl.Limit, 1)
+			} else {
+				remaining = rl.Remaining
+			}
+
+			if remaining <= 0 {
+				if rl.Reset < soonestReset {
+					soonest, soonestReset = i, rl.Reset
+				}
+
+				continue
+			}
+		}
+
+		if remaining > bestRemaining || (remaining == bestRemaining && t.requests < p.tokens[best].requests) {
+			best, bestRemaining = i, remaining
+		}
+	}
+
+	if best == -1 {
+		best = max(soonest, 0)
+	}
+
+	t := p.tokens[best]
+	t.requests++
+
+	if rl, ok := t.resources[resource]; ok && rl.Remaining > 0 {
+		rl.Remaining--
+	}
+
+	return best, t.token
+}
+
+// available reports whether some token still has quota left for resource.
+func (p *TokenPool) available(resource string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now().Unix()
+
+	for _, t := range p.tokens {
+		rl, ok := t.resources[resource]
+		if !ok || rl.Remaining > 0 || rl.Reset <= now {
+			return true
+		}
+	}
+
+	return false
+}
+
+// update records the rate limit reported in resp for the token at idx.
+func (p *TokenPool) update(idx int, resource string, resp *Response) {
+	resource = responseResource(resp, resource)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t := p.tokens[idx]
+
+	if resp.RetryReason == RetryReasonRateLimit {
+		t.rateLimited++
+	}
+
+	if resp.Header.Get(rateRemainigHeader) == "" {
+		return
+	}
+
+	rl := *resp.RateLimit
+	t.resources[resource] = &rl
+}
+
+// maskToken hides a token except for its first and last four characters.
+func maskToken(token string) string {
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
+	}
+
+	return token[:4] + strings.Repeat("*", len(token)-8) + token[len(token)-4:]
+}
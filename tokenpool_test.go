@@ -0,0 +1,172 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newQuotaServer simulates per-token quotas for the core and search resources.
+func newQuotaServer(t *testing.T, quotas map[string]int) (*httptest.Server, map[string]int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	used := map[string]int{}
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		token := r.Header.Get("Authorization")
+		remaining := quotas[token] - used[token]
+
+		w.Header().Set(rateLimitHeader, strconv.Itoa(quotas[token]))
+		w.Header().Set(rateResetHeader, reset)
+		w.Header().Set(rateResourceHeader, ResourceCore)
+
+		if remaining <= 0 {
+			w.Header().Set(rateRemainigHeader, "0")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+
+			return
+		}
+
+		used[token]++
+		w.Header().Set(rateRemainigHeader, strconv.Itoa(remaining-1))
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+
+	return ts, used
+}
+
+func TestTokenPool_PrefersMostRemaining(t *testing.T) {
+	ts, used := newQuotaServer(t, map[string]int{
+		"Bearer small": 2,
+		"Bearer large": 10,
+	})
+	defer ts.Close()
+
+	pool := NewTokenPool("small", "large")
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithTokenPool(pool))
+	require.NoError(t, err)
+
+	for range 8 {
+		_, _, err := client.Users.Get(context.Background(), "octocat")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, used["Bearer small"], "small token is only used to learn its quota")
+	assert.Equal(t, 7, used["Bearer large"])
+}
+
+func TestTokenPool_SkipsExhaustedTokens(t *testing.T) {
+	ts, used := newQuotaServer(t, map[string]int{
+		"Bearer first":  1,
+		"Bearer second": 1,
+		"Bearer third":  1,
+	})
+	defer ts.Close()
+
+	pool := NewTokenPool("first", "second", "third")
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithTokenPool(pool), WithRateLimitRetry(true))
+	require.NoError(t, err)
+
+	for range 3 {
+		_, _, err := client.Users.Get(context.Background(), "octocat")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]int{"Bearer first": 1, "Bearer second": 1, "Bearer third": 1}, used)
+
+	for _, stat := range pool.Stats() {
+		assert.Equal(t, 1, stat.Requests)
+		assert.Equal(t, 0, stat.Resources[ResourceCore].Remaining)
+	}
+}
+
+func TestTokenPool_RetriesWithAnotherToken(t *testing.T) {
+	ts, used := newQuotaServer(t, map[string]int{
+		"Bearer empty": 0,
+		"Bearer full":  5,
+	})
+	defer ts.Close()
+
+	pool := NewTokenPool("empty", "full")
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithTokenPool(pool), WithRateLimitRetry(true))
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	_, _, err = client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(start), time.Second, "should not wait for the reset of the empty token")
+	assert.Equal(t, 1, used["Bearer full"])
+
+	stats := pool.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, 1, stats[0].RateLimited)
+	assert.Equal(t, 0, stats[1].RateLimited)
+}
+
+func TestTokenPool_SeparatesResources(t *testing.T) {
+	pool := NewTokenPool("a", "b")
+
+	idx, _ := pool.acquire(ResourceSearch)
+	pool.update(idx, ResourceSearch, &Response{
+		Response: &http.Response{Header: http.Header{
+			rateRemainigHeader: []string{"0"},
+		}},
+		RateLimit: &RateLimit{Limit: 30, Remaining: 0, Reset: time.Now().Add(time.Minute).Unix()},
+	})
+
+	next, _ := pool.acquire(ResourceSearch)
+	assert.NotEqual(t, idx, next, "exhausted search token is skipped")
+
+	core, _ := pool.acquire(ResourceCore)
+	assert.Contains(t, []int{0, 1}, core)
+}
+
+func TestClient_RequestResource(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"search/code?q=x":         ResourceCodeSearch,
+		"search/repositories?q=x": ResourceSearch,
+		"graphql":                 ResourceGraphQL,
+		"repos/octocat/hello":     ResourceCore,
+	}
+
+	for path, expected := range tests {
+		t.Run(path, func(t *testing.T) {
+			req, err := client.NewRequest(http.MethodGet, path, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, client.requestResource(req))
+		})
+	}
+}
+
+func TestMaskToken(t *testing.T) {
+	assert.Equal(t, "ghp_************cdef", maskToken(fmt.Sprintf("ghp_%s", "0123456789abcdef")))
+	assert.Equal(t, "*****", maskToken("short"))
+}
+
+func TestWithTokenPool_Empty(t *testing.T) {
+	_, err := NewClient(WithTokenPool(NewTokenPool()))
+	require.Error(t, err)
+}
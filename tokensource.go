@@ -0,0 +1,73 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TokenSource supplies the bearer token used to authenticate a request.
+// Token is called for every request, so implementations that fetch
+// tokens remotely should cache them. Implementations must be safe for
+// concurrent use.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenInvalidator is implemented by token sources that cache tokens.
+// The client calls Invalidate when the API rejects a token with
+// 401 Bad credentials, before asking the source for a fresh one.
+type TokenInvalidator interface {
+	Invalidate()
+}
+
+// TokenSourceFunc is an adapter to allow the use of ordinary functions
+// as token sources.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f(ctx).
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource returns a TokenSource that always returns token.
+func StaticTokenSource(token string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// EnvTokenSource returns a TokenSource that reads the token from the
+// environment variable name on every request, so a rotated value is
+// picked up without rebuilding the client.
+func EnvTokenSource(name string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		token := strings.TrimSpace(os.Getenv(name))
+		if token == "" {
+			return "", fmt.Errorf("environment variable %s is empty", name)
+		}
+
+		return token, nil
+	})
+}
+
+// FileTokenSource returns a TokenSource that reads the token from the
+// file at path on every request. Surrounding whitespace is trimmed. This
+// suits tokens rotated on disk by an external agent, such as a vault
+// sidecar or a mounted Kubernetes secret.
+func FileTokenSource(path string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file %s: %w", path, err)
+		}
+
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", path)
+		}
+
+		return token, nil
+	})
+}
@@ -0,0 +1,178 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvTokenSource(t *testing.T) {
+	t.Setenv("GITHUB_TEST_TOKEN", " ghp_env \n")
+
+	src := EnvTokenSource("GITHUB_TEST_TOKEN")
+
+	token, err := src.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_env", token)
+
+	t.Setenv("GITHUB_TEST_TOKEN", "")
+
+	_, err = src.Token(context.Background())
+	require.Error(t, err)
+}
+
+func TestFileTokenSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("ghp_first\n"), 0o600))
+
+	src := FileTokenSource(path)
+
+	token, err := src.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_first", token)
+
+	require.NoError(t, os.WriteFile(path, []byte("ghp_second"), 0o600))
+
+	token, err = src.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_second", token)
+
+	_, err = FileTokenSource(filepath.Join(t.TempDir(), "missing")).Token(context.Background())
+	require.Error(t, err)
+}
+
+type rotatingTokenSource struct {
+	mu          sync.Mutex
+	generation  int
+	invalidated int
+}
+
+func (r *rotatingTokenSource) Token(context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return fmt.Sprintf("token-%d", r.generation), nil
+}
+
+func (r *rotatingTokenSource) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	r.invalidated++
+}
+
+func TestDo_TokenSource(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer ghp_source", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(WithBaseURL(ts.URL), WithTokenSource(StaticTokenSource("ghp_source")))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, _, err := client.Users.Get(context.Background(), "octocat")
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestDo_TokenSourceRefreshOnBadCredentials(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           int
+		body             string
+		headers          map[string]string
+		validToken       string
+		expectedAttempts int32
+		expectError      bool
+	}{
+		{
+			name:             "refreshed token succeeds",
+			status:           http.StatusUnauthorized,
+			body:             `{"message": "Bad credentials"}`,
+			validToken:       "Bearer token-1",
+			expectedAttempts: 2,
+		},
+		{
+			name:             "retried only once",
+			status:           http.StatusUnauthorized,
+			body:             `{"message": "Bad credentials"}`,
+			validToken:       "Bearer never",
+			expectedAttempts: 2,
+			expectError:      true,
+		},
+		{
+			name:             "two-factor challenge is not retried",
+			status:           http.StatusUnauthorized,
+			body:             `{"message": "Must specify two-factor authentication OTP code."}`,
+			headers:          map[string]string{"X-GitHub-OTP": "required; app"},
+			validToken:       "Bearer never",
+			expectedAttempts: 1,
+			expectError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+
+				if r.Header.Get("Authorization") != tt.validToken {
+					for k, v := range tt.headers {
+						w.Header().Set(k, v)
+					}
+
+					w.WriteHeader(tt.status)
+					_, _ = w.Write([]byte(tt.body))
+
+					return
+				}
+
+				_, _ = w.Write([]byte(`{"id": 1}`))
+			}))
+			defer ts.Close()
+
+			src := &rotatingTokenSource{}
+
+			client, err := NewClient(WithBaseURL(ts.URL), WithTokenSource(src))
+			require.NoError(t, err)
+
+			_, _, err = client.Issues.Create(context.Background(), "octocat", "Hello-World", &IssueCreateRequest{Title: "t"})
+			if tt.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectedAttempts, attempts.Load())
+		})
+	}
+}
+
+func TestWithTokenSource_Nil(t *testing.T) {
+	_, err := NewClient(WithTokenSource(nil))
+	require.Error(t, err)
+}
@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -40,17 +41,17 @@ type User struct {
 // This method returns public profile information for any GitHub user,
 // including their name, company, location, bio, and various statistics
 // such as follower count and public repository count.
-func (s *UsersService) Get(ctx context.Context, username string) (*User, *Response, error) {
+func (s *UsersService) Get(ctx context.Context, username string, reqOpts ...RequestOption) (*User, *Response, error) {
 	path := fmt.Sprintf("users/%s", username)
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	user := new(User)
 	
-	resp, err := s.client.Do(ctx, req, user)
+	resp, err := s.client.Do(ctx, req, user, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -62,17 +63,17 @@ func (s *UsersService) Get(ctx context.Context, username string) (*User, *Respon
 // This method returns detailed profile information for the authenticated user,
 // including private information that is only available when authenticated.
 // It requires proper authentication credentials to be configured in the client.
-func (s *UsersService) GetAuthenticated(ctx context.Context) (*User, *Response, error) {
+func (s *UsersService) GetAuthenticated(ctx context.Context, reqOpts ...RequestOption) (*User, *Response, error) {
 	path := "user"
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	user := new(User)
 
-	resp, err := s.client.Do(ctx, req, user)
+	resp, err := s.client.Do(ctx, req, user, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -92,7 +93,7 @@ type UsersListOptions struct {
 // This method returns a paginated list of GitHub users. You can use
 // the Since parameter to specify the user ID to start listing from,
 // which is useful for pagination through large sets of users.
-func (s *UsersService) List(ctx context.Context, opts *UsersListOptions) ([]*User, *Response, error) {
+func (s *UsersService) List(ctx context.Context, opts *UsersListOptions, reqOpts ...RequestOption) ([]*User, *Response, error) {
 	path := "users"
 
 	if opts != nil {
@@ -111,14 +112,14 @@ func (s *UsersService) List(ctx context.Context, opts *UsersListOptions) ([]*Use
 		}
 	}
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	users := new([]*User)
 
-	res, err := s.client.Do(ctx, req, users)
+	res, err := s.client.Do(ctx, req, users, reqOpts...)
 	if err != nil {
 		return nil, res, err
 	}
@@ -126,6 +127,32 @@ func (s *UsersService) List(ctx context.Context, opts *UsersListOptions) ([]*Use
 	return *users, res, nil
 }
 
+// ListAll returns an iterator over all GitHub users.
+// The users endpoint paginates by user ID, so each following page is
+// requested with Since set to the ID of the last user received. The
+// iterator stops once opts.MaxItems users have been yielded or the
+// context is cancelled. Every range over the iterator starts again
+// from opts.
+func (s *UsersService) ListAll(ctx context.Context, opts *UsersListOptions, reqOpts ...RequestOption) iter.Seq2[*User, error] {
+	return eachRange(func() iter.Seq2[*User, error] {
+		o := UsersListOptions{}
+		if opts != nil {
+			o = *opts
+		}
+
+		return paginate(ctx, o.ListOptions, func(ctx context.Context, lo *ListOptions) ([]*User, *Response, error) {
+			o.ListOptions = lo
+
+			users, resp, err := s.List(ctx, &o, reqOpts...)
+			if len(users) != 0 {
+				o.Since = int(users[len(users)-1].ID)
+			}
+
+			return users, resp, err
+		})
+	})
+}
+
 // UserUpdateRequest represents the request body for updating user profile.
 // GitHub API docs: https://docs.github.com/en/rest/users/users#update-the-authenticated-user
 type UserUpdateRequest struct {
@@ -144,17 +171,17 @@ type UserUpdateRequest struct {
 // currently authenticated user, including name, email, company,
 // location, bio, and other profile fields. Only the provided
 // fields will be updated.
-func (s *UsersService) UpdateAuthenticated(ctx context.Context, body UserUpdateRequest) (*User, *Response, error) {
+func (s *UsersService) UpdateAuthenticated(ctx context.Context, body UserUpdateRequest, reqOpts ...RequestOption) (*User, *Response, error) {
 	path := "user"
 
-	req, err := s.client.NewRequest(http.MethodPatch, path, body)
+	req, err := s.client.NewRequest(http.MethodPatch, path, body, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	user := new(User)
 
-	resp, err := s.client.Do(ctx, req, user)
+	resp, err := s.client.Do(ctx, req, user, reqOpts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -165,7 +192,7 @@ func (s *UsersService) UpdateAuthenticated(ctx context.Context, body UserUpdateR
 // ListAuthenticatedUserFollowers retrieves the followers of the authenticated user.
 // This method returns a list of users who are following the authenticated user.
 // The results can be paginated using the ListOptions parameter.
-func (s *UsersService) ListAuthenticatedUserFollowers(ctx context.Context, opts *ListOptions) ([]*User, *Response, error) {
+func (s *UsersService) ListAuthenticatedUserFollowers(ctx context.Context, opts *ListOptions, reqOpts ...RequestOption) ([]*User, *Response, error) {
 	path := "user/followers"
 
 	if opts != nil {
@@ -177,14 +204,14 @@ func (s *UsersService) ListAuthenticatedUserFollowers(ctx context.Context, opts
 		}
 	}
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	users := new([]*User)
 
-	res, err := s.client.Do(ctx, req, users)
+	res, err := s.client.Do(ctx, req, users, reqOpts...)
 	if err != nil {
 		return nil, res, err
 	}
@@ -192,10 +219,18 @@ func (s *UsersService) ListAuthenticatedUserFollowers(ctx context.Context, opts
 	return *users, res, nil
 }
 
+// ListAuthenticatedUserFollowersAll returns an iterator over all followers
+// of the authenticated user, transparently following pagination links.
+func (s *UsersService) ListAuthenticatedUserFollowersAll(ctx context.Context, opts *ListOptions, reqOpts ...RequestOption) iter.Seq2[*User, error] {
+	return paginate(ctx, opts, func(ctx context.Context, lo *ListOptions) ([]*User, *Response, error) {
+		return s.ListAuthenticatedUserFollowers(ctx, lo, reqOpts...)
+	})
+}
+
 // ListAuthenticatedUserFollowings retrieves the users that the authenticated user is following.
 // This method returns a list of users that the authenticated user is following.
 // The results can be paginated using the ListOptions parameter.
-func (s *UsersService) ListAuthenticatedUserFollowings(ctx context.Context, opts *ListOptions) ([]*User, *Response, error) {
+func (s *UsersService) ListAuthenticatedUserFollowings(ctx context.Context, opts *ListOptions, reqOpts ...RequestOption) ([]*User, *Response, error) {
 	path := "user/following"
 
 	if opts != nil {
@@ -207,14 +242,14 @@ func (s *UsersService) ListAuthenticatedUserFollowings(ctx context.Context, opts
 		}
 	}
 
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequest(http.MethodGet, path, nil, reqOpts...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	users := new([]*User)
 
-	res, err := s.client.Do(ctx, req, users)
+	res, err := s.client.Do(ctx, req, users, reqOpts...)
 	if err != nil {
 		return nil, res, err
 	}
@@ -222,20 +257,28 @@ func (s *UsersService) ListAuthenticatedUserFollowings(ctx context.Context, opts
 	return *users, res, nil
 }
 
+// ListAuthenticatedUserFollowingsAll returns an iterator over all users
+// the authenticated user follows, transparently following pagination links.
+func (s *UsersService) ListAuthenticatedUserFollowingsAll(ctx context.Context, opts *ListOptions, reqOpts ...RequestOption) iter.Seq2[*User, error] {
+	return paginate(ctx, opts, func(ctx context.Context, lo *ListOptions) ([]*User, *Response, error) {
+		return s.ListAuthenticatedUserFollowings(ctx, lo, reqOpts...)
+	})
+}
+
 // Follow starts following a user.
 // This method allows the authenticated user to follow another GitHub user.
 // Once followed, the target user will appear in the authenticated user's
 // following list, and the authenticated user will appear in the target
 // user's followers list.
-func (s *UsersService) Follow(ctx context.Context, username string) (*Response, error) {
+func (s *UsersService) Follow(ctx context.Context, username string, reqOpts ...RequestOption) (*Response, error) {
 	path := fmt.Sprintf("user/following/%s", username)
 
-	req, err := s.client.NewRequest(http.MethodPut, path, nil)
+	req, err := s.client.NewRequest(http.MethodPut, path, nil, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(ctx, req, nil)
+	resp, err := s.client.Do(ctx, req, nil, reqOpts...)
 	if err != nil {
 		return resp, err
 	}
@@ -247,15 +290,15 @@ func (s *UsersService) Follow(ctx context.Context, username string) (*Response,
 // This method allows the authenticated user to unfollow a GitHub user
 // they were previously following. This will remove the relationship
 // between the users.
-func (s *UsersService) Unfollow(ctx context.Context, username string) (*Response, error) {
+func (s *UsersService) Unfollow(ctx context.Context, username string, reqOpts ...RequestOption) (*Response, error) {
 	path := fmt.Sprintf("user/following/%s", username)
 
-	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := s.client.NewRequest(http.MethodDelete, path, nil, reqOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.client.Do(ctx, req, nil)
+	resp, err := s.client.Do(ctx, req, nil, reqOpts...)
 	if err != nil {
 		return resp, err
 	}
@@ -0,0 +1,245 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/haadi-coder/github"
+)
+
+const (
+	// AnyAction registers a handler for every action of an event
+	AnyAction = ""
+
+	// DefaultHandlerTimeout is how long a dispatched handler may run.
+	// GitHub abandons a delivery that is not answered within 10 seconds.
+	DefaultHandlerTimeout = 10 * time.Second
+)
+
+// PanicError is returned by Dispatch for a handler that panicked.
+type PanicError struct {
+	// Value is the value passed to panic
+	Value any
+
+	// Stack is the stack trace of the panicking goroutine
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("webhook handler panicked: %v", e.Value)
+}
+
+// EventHandler handles a delivery with a client authenticated for the
+// installation the delivery was sent for.
+type EventHandler func(ctx context.Context, client *github.Client, d *Delivery) error
+
+// Dispatcher routes deliveries to the handlers registered for their event
+// and action. Its Dispatch method is a HandlerFunc:
+//
+//	d := webhook.NewDispatcher(appClient)
+//	d.OnIssues("opened", onIssueOpened)
+//	h, err := webhook.NewHandler(secrets, d.Dispatch)
+type Dispatcher struct {
+	client  *github.Client
+	timeout time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string][]route
+
+	clientsMu sync.Mutex
+	clients   map[int64]*github.Client
+}
+
+type route struct {
+	action string
+	fn     EventHandler
+}
+
+type dispatcherOption func(*Dispatcher) error
+
+// WithHandlerTimeout limits how long each handler may run. A handler that
+// does not return in time fails the delivery with context.DeadlineExceeded.
+func WithHandlerTimeout(timeout time.Duration) dispatcherOption {
+	return func(d *Dispatcher) error {
+		if timeout <= 0 {
+			return fmt.Errorf("handler timeout must be positive, got %v", timeout)
+		}
+
+		d.timeout = timeout
+
+		return nil
+	}
+}
+
+// NewDispatcher creates a dispatcher that hands client to its handlers.
+// Deliveries sent for a GitHub App installation are handled with a client
+// authenticated as that installation instead, which requires client to be
+// authenticated as the app, see github.WithAppAuth.
+func NewDispatcher(client *github.Client, opts ...dispatcherOption) (*Dispatcher, error) {
+	if client == nil {
+		return nil, errors.New("dispatcher client must not be nil")
+	}
+
+	d := &Dispatcher{
+		client:   client,
+		timeout:  DefaultHandlerTimeout,
+		handlers: make(map[string][]route),
+		clients:  make(map[int64]*github.Client),
+	}
+
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return nil, fmt.Errorf("failed to apply dispatcher option: %w", err)
+		}
+	}
+
+	return d, nil
+}
+
+// On registers fn for the action of eventType. AnyAction matches every
+// action, including events without one.
+func (d *Dispatcher) On(eventType, action string, fn EventHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[eventType] = append(d.handlers[eventType], route{action: action, fn: fn})
+}
+
+// OnIssues registers fn for the action of issues events.
+func (d *Dispatcher) OnIssues(action string, fn func(ctx context.Context, client *github.Client, event *IssuesEvent) error) {
+	d.On(EventIssues, action, typed(fn))
+}
+
+// OnIssueComment registers fn for the action of issue_comment events.
+func (d *Dispatcher) OnIssueComment(action string, fn func(ctx context.Context, client *github.Client, event *IssueCommentEvent) error) {
+	d.On(EventIssueComment, action, typed(fn))
+}
+
+// OnPullRequest registers fn for the action of pull_request events.
+func (d *Dispatcher) OnPullRequest(action string, fn func(ctx context.Context, client *github.Client, event *PullRequestEvent) error) {
+	d.On(EventPullRequest, action, typed(fn))
+}
+
+// typed adapts a handler of a typed event to an EventHandler.
+func typed[E any](fn func(ctx context.Context, client *github.Client, event *E) error) EventHandler {
+	return func(ctx context.Context, client *github.Client, d *Delivery) error {
+		event, ok := d.Event.(*E)
+		if !ok {
+			return fmt.Errorf("unexpected %T for %s event", d.Event, d.Type)
+		}
+
+		return fn(ctx, client, event)
+	}
+}
+
+// Dispatch runs the handlers registered for the delivery concurrently and
+// waits for them. Each handler runs with its own timeout and a panic in a
+// handler is returned as a *PanicError. The errors of all failed handlers
+// are joined, and a delivery that failed is redelivered to every handler.
+func (d *Dispatcher) Dispatch(ctx context.Context, delivery *Delivery) error {
+	var envelope *Envelope
+	if event, ok := delivery.Event.(interface{ Common() *Envelope }); ok {
+		envelope = event.Common()
+	} else {
+		envelope = &Envelope{}
+	}
+
+	routes := d.match(delivery.Type, envelope.Action)
+	if len(routes) == 0 {
+		return nil
+	}
+
+	client, err := d.installationClient(envelope.Installation)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, len(routes))
+
+	var wg sync.WaitGroup
+
+	for i, r := range routes {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs[i] = d.run(ctx, r.fn, client, delivery)
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// match returns the handlers registered for the action of eventType.
+func (d *Dispatcher) match(eventType, action string) []route {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var routes []route
+
+	for _, r := range d.handlers[eventType] {
+		if r.action == AnyAction || r.action == action {
+			routes = append(routes, r)
+		}
+	}
+
+	return routes
+}
+
+// run calls fn with a timeout. A handler that ignores the cancellation of
+// its context is left running in the background.
+func (d *Dispatcher) run(ctx context.Context, fn EventHandler, client *github.Client, delivery *Delivery) error {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- &PanicError{Value: v, Stack: debug.Stack()}
+			}
+		}()
+
+		done <- fn(ctx, client, delivery)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("webhook handler did not return: %w", ctx.Err())
+	}
+}
+
+// installationClient returns the client for deliveries sent for
+// installation. Installation clients are created once and reused, so that
+// their tokens are cached.
+func (d *Dispatcher) installationClient(installation *Installation) (*github.Client, error) {
+	if installation == nil || installation.ID == 0 {
+		return d.client, nil
+	}
+
+	d.clientsMu.Lock()
+	defer d.clientsMu.Unlock()
+
+	if client, ok := d.clients[installation.ID]; ok {
+		return client, nil
+	}
+
+	client, err := d.client.Apps.NewInstallationClient(installation.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for installation %d: %w", installation.ID, err)
+	}
+
+	d.clients[installation.ID] = client
+
+	return client, nil
+}
@@ -0,0 +1,240 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/haadi-coder/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDispatcher(t *testing.T, opts ...dispatcherOption) *Dispatcher {
+	t.Helper()
+
+	client, err := github.NewClient()
+	require.NoError(t, err)
+
+	d, err := NewDispatcher(client, opts...)
+	require.NoError(t, err)
+
+	return d
+}
+
+func parseDelivery(t *testing.T, eventType, payload string) *Delivery {
+	t.Helper()
+
+	event, err := ParseEvent(eventType, []byte(payload))
+	require.NoError(t, err)
+
+	return &Delivery{ID: "d-1", Type: eventType, Payload: []byte(payload), Event: event}
+}
+
+func TestDispatcher_Routing(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	var mu sync.Mutex
+	var calls []string
+
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls = append(calls, name)
+	}
+
+	d.OnIssues("opened", func(ctx context.Context, client *github.Client, event *IssuesEvent) error {
+		assert.NotNil(t, client)
+		assert.Equal(t, 42, event.Issue.Number)
+		record("issues.opened")
+
+		return nil
+	})
+	d.OnIssues(AnyAction, func(ctx context.Context, client *github.Client, event *IssuesEvent) error {
+		record("issues.*")
+		return nil
+	})
+	d.OnIssues("closed", func(ctx context.Context, client *github.Client, event *IssuesEvent) error {
+		record("issues.closed")
+		return nil
+	})
+	d.OnPullRequest("synchronize", func(ctx context.Context, client *github.Client, event *PullRequestEvent) error {
+		assert.Equal(t, "def", event.After)
+		record("pull_request.synchronize")
+
+		return nil
+	})
+	d.OnIssueComment("created", func(ctx context.Context, client *github.Client, event *IssueCommentEvent) error {
+		record("issue_comment.created")
+		return nil
+	})
+	d.On("push", AnyAction, func(ctx context.Context, client *github.Client, delivery *Delivery) error {
+		record("push")
+		return nil
+	})
+
+	h, err := NewHandler([]string{"secret"}, d.Dispatch)
+	require.NoError(t, err)
+
+	payload := `{"action": "opened", "issue": {"number": 42}}`
+	assert.Equal(t, http.StatusNoContent, serve(h, newDelivery(t, "secret", EventIssues, "d-1", payload)))
+	assert.ElementsMatch(t, []string{"issues.opened", "issues.*"}, calls)
+
+	calls = nil
+	payload = `{"action": "synchronize", "after": "def", "pull_request": {"number": 5}}`
+	assert.Equal(t, http.StatusNoContent, serve(h, newDelivery(t, "secret", EventPullRequest, "d-2", payload)))
+	assert.Equal(t, []string{"pull_request.synchronize"}, calls)
+
+	calls = nil
+	payload = `{"action": "created", "comment": {"id": 3}}`
+	assert.Equal(t, http.StatusNoContent, serve(h, newDelivery(t, "secret", EventIssueComment, "d-3", payload)))
+	assert.Equal(t, []string{"issue_comment.created"}, calls)
+
+	calls = nil
+	assert.Equal(t, http.StatusNoContent, serve(h, newDelivery(t, "secret", "push", "d-4", `{"ref": "refs/heads/main"}`)))
+	assert.Equal(t, []string{"push"}, calls)
+
+	calls = nil
+	assert.Equal(t, http.StatusNoContent, serve(h, newDelivery(t, "secret", EventPing, "d-5", `{"zen": "Keep it simple."}`)))
+	assert.Empty(t, calls)
+}
+
+func TestDispatcher_Concurrent(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	// each handler waits for the other, so they only return if they run
+	// at the same time
+	var started sync.WaitGroup
+	started.Add(2)
+
+	for range 2 {
+		d.OnIssues("opened", func(ctx context.Context, client *github.Client, event *IssuesEvent) error {
+			started.Done()
+			started.Wait()
+
+			return nil
+		})
+	}
+
+	require.NoError(t, d.Dispatch(context.Background(), parseDelivery(t, EventIssues, `{"action": "opened"}`)))
+}
+
+func TestDispatcher_Errors(t *testing.T) {
+	d := newTestDispatcher(t, WithHandlerTimeout(20*time.Millisecond))
+
+	release := make(chan struct{})
+	defer close(release)
+
+	var succeeded atomic.Bool
+
+	d.OnIssues(AnyAction, func(ctx context.Context, client *github.Client, event *IssuesEvent) error {
+		panic("boom")
+	})
+	d.OnIssues(AnyAction, func(ctx context.Context, client *github.Client, event *IssuesEvent) error {
+		<-release
+		return nil
+	})
+	d.OnIssues(AnyAction, func(ctx context.Context, client *github.Client, event *IssuesEvent) error {
+		return errors.New("failed")
+	})
+	d.OnIssues(AnyAction, func(ctx context.Context, client *github.Client, event *IssuesEvent) error {
+		succeeded.Store(true)
+		return nil
+	})
+
+	err := d.Dispatch(context.Background(), parseDelivery(t, EventIssues, `{"action": "opened"}`))
+	require.Error(t, err)
+
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "failed")
+	assert.True(t, succeeded.Load())
+}
+
+func TestDispatcher_InstallationClient(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pemKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	var issued atomic.Int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app/installations/99/access_tokens" {
+			issued.Add(1)
+			_, _ = w.Write([]byte(`{"token": "ghs_installation", "expires_at": "2030-01-01T00:00:00Z"}`))
+
+			return
+		}
+
+		assert.Equal(t, "Bearer ghs_installation", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"id": 1, "name": "hello-world"}`))
+	}))
+	defer ts.Close()
+
+	app, err := github.NewClient(github.WithBaseURL(ts.URL), github.WithAppAuth(42, pemKey))
+	require.NoError(t, err)
+
+	d, err := NewDispatcher(app)
+	require.NoError(t, err)
+
+	var clients []*github.Client
+
+	d.OnIssues("opened", func(ctx context.Context, client *github.Client, event *IssuesEvent) error {
+		clients = append(clients, client)
+
+		repo, _, err := client.Repositories.Get(ctx, "octocat", "hello-world")
+		if err != nil {
+			return err
+		}
+
+		assert.Equal(t, "hello-world", repo.Name)
+
+		return nil
+	})
+
+	require.NoError(t, d.Dispatch(context.Background(), parseDelivery(t, EventIssues, issuesPayload)))
+	require.NoError(t, d.Dispatch(context.Background(), parseDelivery(t, EventIssues, issuesPayload)))
+
+	require.Len(t, clients, 2)
+	assert.NotSame(t, app, clients[0])
+	assert.Same(t, clients[0], clients[1], "installation clients are reused")
+	assert.Equal(t, int32(1), issued.Load())
+
+	// deliveries for an installation need an app client
+	d = newTestDispatcher(t)
+	d.OnIssues("opened", func(ctx context.Context, client *github.Client, event *IssuesEvent) error {
+		t.Error("handler must not be called")
+		return nil
+	})
+
+	require.Error(t, d.Dispatch(context.Background(), parseDelivery(t, EventIssues, issuesPayload)))
+}
+
+func TestNewDispatcher_Errors(t *testing.T) {
+	_, err := NewDispatcher(nil)
+	require.Error(t, err)
+
+	client, err := github.NewClient()
+	require.NoError(t, err)
+
+	_, err = NewDispatcher(client, WithHandlerTimeout(0))
+	require.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/haadi-coder/github"
+)
+
+// Event names sent in the X-GitHub-Event header.
+const (
+	EventPing         = "ping"
+	EventIssues       = "issues"
+	EventIssueComment = "issue_comment"
+	EventPullRequest  = "pull_request"
+)
+
+// Installation identifies the GitHub App installation a delivery was
+// sent for.
+type Installation struct {
+	ID     int64  `json:"id"`
+	NodeID string `json:"node_id"`
+}
+
+// Envelope contains the fields shared by the payloads of all events.
+// Fields that do not apply to an event are nil.
+type Envelope struct {
+	// Action is the activity that triggered the event, such as opened
+	Action string `json:"action,omitempty"`
+
+	// Repository is the repository where the event occurred
+	Repository *github.Repository `json:"repository,omitempty"`
+
+	// Sender is the user that triggered the event
+	Sender *github.User `json:"sender,omitempty"`
+
+	// Installation is set for deliveries to GitHub Apps
+	Installation *Installation `json:"installation,omitempty"`
+}
+
+// Common returns the fields shared by all events.
+func (e *Envelope) Common() *Envelope { return e }
+
+// PingEvent is sent when a webhook is created.
+// GitHub docs: https://docs.github.com/en/webhooks/webhook-events-and-payloads#ping
+type PingEvent struct {
+	Envelope
+
+	Zen    string `json:"zen"`
+	HookID int64  `json:"hook_id"`
+}
+
+// IssuesEvent is sent when an issue is opened, edited, closed, labeled
+// or otherwise changed.
+// GitHub docs: https://docs.github.com/en/webhooks/webhook-events-and-payloads#issues
+type IssuesEvent struct {
+	Envelope
+
+	Issue *github.Issue `json:"issue"`
+
+	// Changes describes the previous values of edited fields
+	Changes json.RawMessage `json:"changes,omitempty"`
+
+	// Label is the label that was added or removed
+	Label *github.Label `json:"label,omitempty"`
+
+	// Assignee is the user that was assigned or unassigned
+	Assignee *github.User `json:"assignee,omitempty"`
+}
+
+// IssueCommentEvent is sent when a comment on an issue or pull request
+// is created, edited or deleted.
+// GitHub docs: https://docs.github.com/en/webhooks/webhook-events-and-payloads#issue_comment
+type IssueCommentEvent struct {
+	Envelope
+
+	Issue   *github.Issue        `json:"issue"`
+	Comment *github.IssueComment `json:"comment"`
+
+	// Changes describes the previous values of edited fields
+	Changes json.RawMessage `json:"changes,omitempty"`
+}
+
+// PullRequestEvent is sent when a pull request is opened, synchronized,
+// closed or otherwise changed.
+// GitHub docs: https://docs.github.com/en/webhooks/webhook-events-and-payloads#pull_request
+type PullRequestEvent struct {
+	Envelope
+
+	Number      int                 `json:"number"`
+	PullRequest *github.PullRequest `json:"pull_request"`
+
+	// Before and After are the head commits before and after a
+	// synchronize action
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+
+	// Changes describes the previous values of edited fields
+	Changes json.RawMessage `json:"changes,omitempty"`
+
+	// Label is the label that was added or removed
+	Label *github.Label `json:"label,omitempty"`
+
+	// Assignee is the user that was assigned or unassigned
+	Assignee *github.User `json:"assignee,omitempty"`
+
+	// RequestedReviewer is the user whose review was requested or whose
+	// review request was removed
+	RequestedReviewer *github.User `json:"requested_reviewer,omitempty"`
+}
+
+// GenericEvent holds the payload of an event without a typed struct.
+type GenericEvent struct {
+	Envelope
+
+	// Raw is the complete payload
+	Raw json.RawMessage `json:"-"`
+}
+
+// ParseEvent decodes the payload of the named event into its typed struct:
+// *PingEvent, *IssuesEvent, *IssueCommentEvent or *PullRequestEvent.
+// Other events are decoded into a *GenericEvent.
+func ParseEvent(eventType string, payload []byte) (any, error) {
+	var event any
+
+	switch eventType {
+	case EventPing:
+		event = new(PingEvent)
+	case EventIssues:
+		event = new(IssuesEvent)
+	case EventIssueComment:
+		event = new(IssueCommentEvent)
+	case EventPullRequest:
+		event = new(PullRequestEvent)
+	default:
+		event = &GenericEvent{Raw: payload}
+	}
+
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", eventType, err)
+	}
+
+	return event, nil
+}
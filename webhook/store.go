@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DeliveryStore remembers the IDs of received deliveries to detect
+// replays. Implementations must be safe for concurrent use.
+type DeliveryStore interface {
+	// Claim records id and reports whether it was not recorded before
+	Claim(id string) bool
+
+	// Release forgets id, so that a redelivery of a delivery that failed
+	// to be handled is accepted
+	Release(id string)
+}
+
+// MemoryDeliveryStore is an in-memory DeliveryStore that remembers
+// delivery IDs for a fixed duration.
+type MemoryDeliveryStore struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	ll    *list.List
+	items map[string]*list.Element
+	now   func() time.Time
+}
+
+type deliveryItem struct {
+	id      string
+	expires time.Time
+}
+
+// NewMemoryDeliveryStore creates a store that remembers delivery IDs for
+// ttl. Replays older than ttl are not detected.
+func NewMemoryDeliveryStore(ttl time.Duration) *MemoryDeliveryStore {
+	return &MemoryDeliveryStore{
+		ttl:   ttl,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+		now:   time.Now,
+	}
+}
+
+// Claim records id and reports whether it was not recorded before.
+func (m *MemoryDeliveryStore) Claim(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.prune(now)
+
+	if _, ok := m.items[id]; ok {
+		return false
+	}
+
+	m.items[id] = m.ll.PushBack(&deliveryItem{id: id, expires: now.Add(m.ttl)})
+
+	return true
+}
+
+// Release forgets id.
+func (m *MemoryDeliveryStore) Release(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[id]; ok {
+		m.ll.Remove(el)
+		delete(m.items, id)
+	}
+}
+
+// prune removes the expired IDs, which are at the front of the list
+// because all IDs live for the same duration.
+func (m *MemoryDeliveryStore) prune(now time.Time) {
+	for el := m.ll.Front(); el != nil; el = m.ll.Front() {
+		item := el.Value.(*deliveryItem)
+		if now.Before(item.expires) {
+			return
+		}
+
+		m.ll.Remove(el)
+		delete(m.items, item.id)
+	}
+}
@@ -0,0 +1,274 @@
+// Package webhook receives GitHub webhook deliveries. Its Handler verifies
+// the HMAC signature of every delivery against one or more secrets, limits
+// the size of the payload, rejects replayed deliveries and decodes the
+// payload into typed events that reuse the structs of the github package.
+//
+// GitHub docs: https://docs.github.com/en/webhooks/using-webhooks/validating-webhook-deliveries
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	signatureHeader = "X-Hub-Signature-256"
+	eventHeader     = "X-GitHub-Event"
+	deliveryHeader  = "X-GitHub-Delivery"
+	hookIDHeader    = "X-GitHub-Hook-ID"
+
+	signaturePrefix = "sha256="
+
+	// DefaultMaxBodySize is the largest payload GitHub delivers, 25 MB
+	DefaultMaxBodySize = 25 << 20
+
+	// DefaultReplayWindow is how long delivery IDs are remembered by the
+	// default delivery store
+	DefaultReplayWindow = 24 * time.Hour
+)
+
+// Errors reported while verifying a delivery.
+var (
+	ErrMissingSignature = errors.New("webhook: missing signature")
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrPayloadTooLarge  = errors.New("webhook: payload too large")
+	ErrReplayed         = errors.New("webhook: delivery already received")
+)
+
+// Delivery is a verified webhook delivery.
+type Delivery struct {
+	// ID is the unique ID of the delivery from the X-GitHub-Delivery header
+	ID string
+
+	// Type is the event name from the X-GitHub-Event header, such as issues
+	Type string
+
+	// HookID is the ID of the webhook from the X-GitHub-Hook-ID header
+	HookID string
+
+	// Payload is the raw JSON payload
+	Payload []byte
+
+	// Event is the decoded payload, see ParseEvent
+	Event any
+}
+
+// HandlerFunc processes a verified delivery. Returning an error answers
+// the delivery with 500 Internal Server Error, so that it can be
+// redelivered.
+type HandlerFunc func(ctx context.Context, d *Delivery) error
+
+// Handler is an http.Handler that receives webhook deliveries and passes
+// the verified ones to a HandlerFunc.
+type Handler struct {
+	secrets     [][]byte
+	fn          HandlerFunc
+	maxBodySize int64
+	deliveries  DeliveryStore
+}
+
+type option func(*Handler) error
+
+// WithMaxBodySize limits the size of accepted payloads to n bytes.
+// Larger deliveries are answered with 413 Request Entity Too Large.
+func WithMaxBodySize(n int64) option {
+	return func(h *Handler) error {
+		if n <= 0 {
+			return fmt.Errorf("max body size must be positive, got %d", n)
+		}
+
+		h.maxBodySize = n
+
+		return nil
+	}
+}
+
+// WithDeliveryStore configures the store that remembers delivery IDs to
+// reject replays. A nil store disables replay protection.
+func WithDeliveryStore(store DeliveryStore) option {
+	return func(h *Handler) error {
+		h.deliveries = store
+
+		return nil
+	}
+}
+
+// NewHandler creates a handler that verifies deliveries against secrets
+// and passes them to fn. A delivery is accepted if it is signed with any
+// of the secrets, which allows rotating the secret without downtime.
+// By default payloads are limited to DefaultMaxBodySize and delivery IDs
+// are remembered in memory for DefaultReplayWindow.
+func NewHandler(secrets []string, fn HandlerFunc, opts ...option) (*Handler, error) {
+	if fn == nil {
+		return nil, errors.New("webhook handler func must not be nil")
+	}
+
+	if len(secrets) == 0 {
+		return nil, errors.New("at least one webhook secret is required")
+	}
+
+	h := &Handler{
+		fn:          fn,
+		maxBodySize: DefaultMaxBodySize,
+		deliveries:  NewMemoryDeliveryStore(DefaultReplayWindow),
+	}
+
+	for _, secret := range secrets {
+		if secret == "" {
+			return nil, errors.New("webhook secret must not be empty")
+		}
+
+		h.secrets = append(h.secrets, []byte(secret))
+	}
+
+	for _, opt := range opts {
+		if err := opt(h); err != nil {
+			return nil, fmt.Errorf("failed to apply webhook option: %w", err)
+		}
+	}
+
+	return h, nil
+}
+
+// ServeHTTP verifies and handles a single delivery.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	d, err := h.verify(w, r)
+	if err != nil {
+		status := http.StatusBadRequest
+
+		switch {
+		case errors.Is(err, ErrPayloadTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature):
+			status = http.StatusUnauthorized
+		case errors.Is(err, ErrReplayed):
+			status = http.StatusConflict
+		}
+
+		http.Error(w, err.Error(), status)
+
+		return
+	}
+
+	if err := h.fn(r.Context(), d); err != nil {
+		if h.deliveries != nil {
+			h.deliveries.Release(d.ID)
+		}
+
+		http.Error(w, "failed to handle delivery", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify reads, authenticates and decodes the delivery of r.
+func (h *Handler) verify(w http.ResponseWriter, r *http.Request) (*Delivery, error) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, ErrPayloadTooLarge
+		}
+
+		return nil, fmt.Errorf("failed to read payload: %w", err)
+	}
+
+	if err := ValidateSignature(r.Header.Get(signatureHeader), payload, h.secrets...); err != nil {
+		return nil, err
+	}
+
+	d := &Delivery{
+		ID:      r.Header.Get(deliveryHeader),
+		Type:    r.Header.Get(eventHeader),
+		HookID:  r.Header.Get(hookIDHeader),
+		Payload: payload,
+	}
+
+	if d.Type == "" {
+		return nil, fmt.Errorf("missing %s header", eventHeader)
+	}
+
+	if h.deliveries != nil {
+		if d.ID == "" {
+			return nil, fmt.Errorf("missing %s header", deliveryHeader)
+		}
+
+		if !h.deliveries.Claim(d.ID) {
+			return nil, ErrReplayed
+		}
+	}
+
+	d.Event, err = ParseEvent(d.Type, payload)
+	if err != nil {
+		if h.deliveries != nil {
+			h.deliveries.Release(d.ID)
+		}
+
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// ValidateSignature checks that signature, the value of the
+// X-Hub-Signature-256 header, is the HMAC-SHA256 of payload under one of
+// the secrets. The comparison takes constant time.
+func ValidateSignature(signature string, payload []byte, secrets ...[]byte) error {
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	hexSum, ok := strings.CutPrefix(signature, signaturePrefix)
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	sum, err := hex.DecodeString(hexSum)
+	if err != nil || len(sum) != sha256.Size {
+		return ErrInvalidSignature
+	}
+
+	// check every secret, so the time taken does not tell which one matched
+	valid := false
+
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(payload)
+
+		if hmac.Equal(mac.Sum(nil), sum) {
+			valid = true
+		}
+	}
+
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Sign returns the X-Hub-Signature-256 value of payload under secret.
+// It is useful to test webhook handlers.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
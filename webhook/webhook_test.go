@@ -0,0 +1,265 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const issuesPayload = `{
+	"action": "opened",
+	"issue": {"id": 1, "node_id": "I_1", "number": 42, "title": "Found a bug", "user": {"login": "octocat"}},
+	"repository": {"id": 1296269, "full_name": "octocat/hello-world", "created_at": 1672574400},
+	"sender": {"login": "octocat"},
+	"installation": {"id": 99}
+}`
+
+func newDelivery(t *testing.T, secret, event, id, payload string) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", id)
+	req.Header.Set("X-GitHub-Hook-ID", "7")
+	req.Header.Set("Content-Type", "application/json")
+
+	if secret != "" {
+		req.Header.Set("X-Hub-Signature-256", Sign(secret, []byte(payload)))
+	}
+
+	return req
+}
+
+func serve(h http.Handler, req *http.Request) int {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec.Code
+}
+
+func TestHandler(t *testing.T) {
+	var got *Delivery
+
+	h, err := NewHandler([]string{"secret"}, func(ctx context.Context, d *Delivery) error {
+		got = d
+		return nil
+	})
+	require.NoError(t, err)
+
+	code := serve(h, newDelivery(t, "secret", EventIssues, "d-1", issuesPayload))
+	require.Equal(t, http.StatusNoContent, code)
+	require.NotNil(t, got)
+
+	assert.Equal(t, "d-1", got.ID)
+	assert.Equal(t, EventIssues, got.Type)
+	assert.Equal(t, "7", got.HookID)
+	assert.JSONEq(t, issuesPayload, string(got.Payload))
+
+	event, ok := got.Event.(*IssuesEvent)
+	require.True(t, ok)
+	assert.Equal(t, "opened", event.Action)
+	assert.Equal(t, 42, event.Issue.Number)
+	assert.Equal(t, "I_1", event.Issue.NodeID)
+	assert.Equal(t, "octocat/hello-world", event.Repository.Fullname)
+	assert.Equal(t, 2023, event.Repository.CreatedAt.Year())
+	assert.Equal(t, "octocat", event.Sender.Login)
+	assert.Equal(t, int64(99), event.Installation.ID)
+}
+
+func TestHandler_Rejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      func() *http.Request
+		expected int
+	}{
+		{
+			name: "wrong method",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/webhook", nil)
+			},
+			expected: http.StatusMethodNotAllowed,
+		},
+		{
+			name: "missing signature",
+			req: func() *http.Request {
+				return newDelivery(t, "", EventIssues, "d-1", issuesPayload)
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong secret",
+			req: func() *http.Request {
+				return newDelivery(t, "other", EventIssues, "d-1", issuesPayload)
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "tampered payload",
+			req: func() *http.Request {
+				req := newDelivery(t, "secret", EventIssues, "d-1", issuesPayload)
+				req.Body = io.NopCloser(strings.NewReader(strings.Replace(issuesPayload, "opened", "closed", 1)))
+
+				return req
+			},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name: "too large",
+			req: func() *http.Request {
+				return newDelivery(t, "secret", EventIssues, "d-1", `{"action": "`+strings.Repeat("x", 1024)+`"}`)
+			},
+			expected: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "missing event",
+			req: func() *http.Request {
+				return newDelivery(t, "secret", "", "d-1", issuesPayload)
+			},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name: "missing delivery ID",
+			req: func() *http.Request {
+				return newDelivery(t, "secret", EventIssues, "", issuesPayload)
+			},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name: "malformed payload",
+			req: func() *http.Request {
+				return newDelivery(t, "secret", EventIssues, "d-1", `{"issue": []}`)
+			},
+			expected: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHandler([]string{"secret"}, func(ctx context.Context, d *Delivery) error {
+				t.Error("handler must not be called")
+				return nil
+			}, WithMaxBodySize(1024))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, serve(h, tt.req()))
+		})
+	}
+}
+
+func TestHandler_SecretRotation(t *testing.T) {
+	calls := 0
+
+	h, err := NewHandler([]string{"new", "old"}, func(ctx context.Context, d *Delivery) error {
+		calls++
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, serve(h, newDelivery(t, "new", EventIssues, "d-1", issuesPayload)))
+	assert.Equal(t, http.StatusNoContent, serve(h, newDelivery(t, "old", EventIssues, "d-2", issuesPayload)))
+	assert.Equal(t, 2, calls)
+}
+
+func TestHandler_Replay(t *testing.T) {
+	var calls atomic.Int32
+	fail := true
+
+	h, err := NewHandler([]string{"secret"}, func(ctx context.Context, d *Delivery) error {
+		calls.Add(1)
+
+		if fail {
+			return errors.New("temporary failure")
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusInternalServerError, serve(h, newDelivery(t, "secret", EventIssues, "d-1", issuesPayload)))
+
+	fail = false
+
+	assert.Equal(t, http.StatusNoContent, serve(h, newDelivery(t, "secret", EventIssues, "d-1", issuesPayload)),
+		"a failed delivery can be redelivered")
+	assert.Equal(t, http.StatusConflict, serve(h, newDelivery(t, "secret", EventIssues, "d-1", issuesPayload)))
+	assert.Equal(t, int32(2), calls.Load())
+
+	h, err = NewHandler([]string{"secret"}, func(ctx context.Context, d *Delivery) error {
+		return nil
+	}, WithDeliveryStore(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, serve(h, newDelivery(t, "secret", EventIssues, "d-1", issuesPayload)))
+	assert.Equal(t, http.StatusNoContent, serve(h, newDelivery(t, "secret", EventIssues, "d-1", issuesPayload)))
+}
+
+func TestMemoryDeliveryStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	store := NewMemoryDeliveryStore(time.Hour)
+	store.now = func() time.Time { return now }
+
+	assert.True(t, store.Claim("a"))
+	assert.False(t, store.Claim("a"))
+
+	now = now.Add(30 * time.Minute)
+	assert.True(t, store.Claim("b"))
+
+	now = now.Add(31 * time.Minute)
+	assert.True(t, store.Claim("a"), "expired IDs are forgotten")
+	assert.False(t, store.Claim("b"))
+
+	store.Release("b")
+	assert.True(t, store.Claim("b"))
+}
+
+func TestNewHandler_Errors(t *testing.T) {
+	fn := func(ctx context.Context, d *Delivery) error { return nil }
+
+	_, err := NewHandler(nil, fn)
+	require.Error(t, err)
+
+	_, err = NewHandler([]string{""}, fn)
+	require.Error(t, err)
+
+	_, err = NewHandler([]string{"secret"}, nil)
+	require.Error(t, err)
+
+	_, err = NewHandler([]string{"secret"}, fn, WithMaxBodySize(0))
+	require.Error(t, err)
+}
+
+func TestParseEvent(t *testing.T) {
+	event, err := ParseEvent(EventPullRequest, []byte(`{
+		"action": "synchronize", "number": 5, "before": "abc", "after": "def",
+		"pull_request": {"number": 5, "title": "Fix"}
+	}`))
+	require.NoError(t, err)
+
+	pr, ok := event.(*PullRequestEvent)
+	require.True(t, ok)
+	assert.Equal(t, "synchronize", pr.Action)
+	assert.Equal(t, "def", pr.After)
+	assert.Equal(t, "Fix", pr.PullRequest.Title)
+
+	event, err = ParseEvent(EventIssueComment, []byte(`{"action": "created", "comment": {"id": 3, "body": "LGTM"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "LGTM", event.(*IssueCommentEvent).Comment.Body)
+
+	event, err = ParseEvent("push", []byte(`{"ref": "refs/heads/main", "repository": {"pushed_at": 1672574400}}`))
+	require.NoError(t, err)
+
+	generic, ok := event.(*GenericEvent)
+	require.True(t, ok)
+	assert.Contains(t, string(generic.Raw), "refs/heads/main")
+	assert.Equal(t, 2023, generic.Repository.PushedAt.Year())
+}